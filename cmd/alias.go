@@ -0,0 +1,159 @@
+// Copyright © 2020 Philipp Galichkin
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/table"
+	"github.com/philgal/jtl/internal/config"
+	"github.com/philgal/jtl/internal/duration"
+	"github.com/philgal/jtl/internal/jira"
+	"github.com/philgal/jtl/internal/model"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var aliasNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// aliasCmd represents the alias command
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Manages ticket aliases in config",
+	Long: `Aliases are short names of tickets, used instead of them by log, report and push. See 'jtl help log'.
+The commands edit <alias> map of the config file, keeping its comments. Alias names are case-insensitive.
+
+Examples:
+  jtl alias add jt1 JIRATICKET-1
+  jtl alias add standup MEET-7 -m "Daily standup" -t 15m --estimate leave
+  jtl alias rm jt1
+  jtl alias ls
+`,
+}
+
+var aliasAddCmd = &cobra.Command{
+	Use:   "add <alias> <ticket>",
+	Short: "Adds an alias of the ticket, or replaces the existing one",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		a := model.Alias{Name: strings.ToLower(args[0]), Ticket: strings.TrimSpace(args[1])}
+		a.Comment, _ = cmd.Flags().GetString(messageCmdStr)
+		a.TimeSpent, _ = cmd.Flags().GetString(timeCmdStr)
+		a.Visibility, _ = cmd.Flags().GetString("visibility")
+		a.Estimate, _ = cmd.Flags().GetString("estimate")
+		if err := validateAlias(a); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		model.ValidateJiraTicketFormat(a.Ticket)
+		var value any = a.Ticket
+		if a.HasDefaults() {
+			value = a
+		}
+		if err := config.SetInFile(configFile(), []string{"alias", a.Name}, value); err != nil {
+			fmt.Println("Error saving alias:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Alias %v of %v saved\n", a.Name, a.Ticket)
+	},
+}
+
+var aliasRmCmd = &cobra.Command{
+	Use:   "rm <alias>",
+	Short: "Removes the alias",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := strings.ToLower(args[0])
+		removed, err := config.RemoveFromFile(configFile(), []string{"alias", name})
+		if err == nil && removed {
+			// estimate rule of a removed alias would fail reading the rules
+			_, err = config.RemoveFromFile(configFile(), []string{"estimate", "aliases", name})
+		}
+		if err != nil {
+			fmt.Println("Error removing alias:", err)
+			os.Exit(1)
+		}
+		if !removed {
+			fmt.Printf("Alias %v not found\n", name)
+			os.Exit(1)
+		}
+		fmt.Printf("Alias %v removed\n", name)
+	},
+}
+
+var aliasLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "Lists aliases",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		aliases, err := model.Aliases()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		summaries := issueSummaries()
+		t := table.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		t.AppendHeader(table.Row{"alias", "ticket", "summary", "comment", "time", "visibility", "estimate"})
+		for _, name := range model.AliasNames(aliases) {
+			a := aliases[name]
+			t.AppendRow(table.Row{a.Name, a.Ticket, summaries[a.Ticket], a.Comment, a.TimeSpent, a.Visibility, a.Estimate})
+		}
+		t.Render()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(aliasCmd)
+	aliasCmd.AddCommand(aliasAddCmd, aliasRmCmd, aliasLsCmd)
+	aliasAddCmd.Flags().StringP(messageCmdStr, "m", "", "Default comment of the alias' records")
+	aliasAddCmd.Flags().StringP(timeCmdStr, "t", "", "Default time spent of the alias' records, e.g. 15m")
+	aliasAddCmd.Flags().String("visibility", "", "Default visibility of the alias' records, e.g. role:Developers")
+	aliasAddCmd.Flags().String("estimate", "", "Estimate adjustment of the alias' records: auto, leave, new:<estimate> or manual:<reduce by>")
+}
+
+func validateAlias(a model.Alias) error {
+	if !aliasNamePattern.MatchString(a.Name) {
+		return fmt.Errorf("invalid alias %q, use letters, digits, - and _", a.Name)
+	}
+	if a.Ticket == "" {
+		return errors.New("ticket is required")
+	}
+	if a.TimeSpent != "" && duration.ToMinutes(a.TimeSpent) <= 0 {
+		return fmt.Errorf("invalid time %q, e.g. 1h 30m", a.TimeSpent)
+	}
+	if _, err := jira.ParseVisibility(a.Visibility); err != nil {
+		return err
+	}
+	if a.Estimate != "" {
+		if _, err := model.ParseEstimateAdjustment(a.Estimate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// configFile returns path of the config file in use
+func configFile() string {
+	if file := viper.ConfigFileUsed(); file != "" {
+		return file
+	}
+	return path.Join(config.Dir(), "config.yaml")
+}
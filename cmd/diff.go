@@ -0,0 +1,141 @@
+// Copyright © 2020 Philipp Galichkin
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/philgal/jtl/internal/config"
+	"github.com/philgal/jtl/internal/credentials"
+	"github.com/philgal/jtl/internal/csv"
+	"github.com/philgal/jtl/internal/duration"
+	"github.com/philgal/jtl/internal/jira"
+	"github.com/philgal/jtl/internal/reconcile"
+	"github.com/philgal/jtl/internal/report"
+	"github.com/philgal/jtl/internal/rest"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Shows differences between the data file and worklogs in Jira",
+	Long: `Fetches a remote worklog for every pushed record of the data file, and all your worklogs in the period of the data file, and reports:
+
+  - pushed records, which worklogs are deleted in Jira
+  - pushed records, which time spent, started or comment are changed in Jira
+  - records, which are not pushed yet
+  - worklogs, which exist only in Jira
+
+Use --json to get a machine-readable output. Like diff(1), the command exits with status 1 if there are differences.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if viper.GetString("host") == "" {
+			fmt.Println("Jira host is not set in config")
+			os.Exit(1)
+		}
+		client := newJiraClient(resolveCredentials(credentials.Default()), rest.HTTPClient)
+		csvFile := csv.NewCsvFile(config.DataFilePath())
+		csvFile.ReadAll()
+		asJSON, _ := cmd.Flags().GetBool("json")
+		status, err := printDiff(os.Stdout, client, csvFile.Records, asJSON)
+		if err != nil {
+			fmt.Println("Diff failed:", err)
+			log.Fatalln("Diff failed:", err)
+		}
+		os.Exit(status)
+	},
+}
+
+// printDiff prints the difference of the records with Jira as a table or JSON.
+// Returns the exit status: 0 if the records are in sync, 1 if there are differences.
+func printDiff(w io.Writer, client *jira.Client, records []csv.Record, asJSON bool) (int, error) {
+	result, err := diff(client, records)
+	if err != nil {
+		return 0, err
+	}
+	if asJSON {
+		out := json.NewEncoder(w)
+		out.SetIndent("", "  ")
+		if err := out.Encode(result); err != nil {
+			return 0, err
+		}
+	} else {
+		report.NewDiffReport(result).PrintTo(w)
+	}
+	if result.IsEmpty() {
+		return 0, nil
+	}
+	return 1, nil
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().Bool("json", false, "Print the difference as JSON")
+}
+
+// diff fetches remote worklogs for the records and compares them
+func diff(client *jira.Client, records []csv.Record) (reconcile.Result, error) {
+	pushed := map[string]*jira.Worklog{}
+	for _, rec := range records {
+		if !rec.IsPushed() {
+			continue
+		}
+		worklog, err := client.Worklog(rec.Ticket, rec.ID)
+		if err != nil && !jira.IsNotFound(err) {
+			return reconcile.Result{}, fmt.Errorf("cannot fetch worklog %v of %v: %w", rec.ID, rec.Ticket, err)
+		}
+		pushed[rec.ID] = worklog
+	}
+	me, err := client.Myself()
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("cannot resolve current user: %w", err)
+	}
+	from, to := recordsPeriod(records)
+	userWorklogs, err := client.UserWorklogs(me, from, to)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Diff(records, pushed, userWorklogs), nil
+}
+
+// recordsPeriod returns the months covered by the records, the current month if there are no records
+func recordsPeriod(records []csv.Record) (time.Time, time.Time) {
+	var first, last time.Time
+	for _, rec := range records {
+		started := duration.ParseTime(rec.StartedTs).Time
+		if started.IsZero() {
+			continue
+		}
+		if first.IsZero() || started.Before(first) {
+			first = started
+		}
+		if last.IsZero() || started.After(last) {
+			last = started
+		}
+	}
+	if first.IsZero() {
+		first, last = time.Now(), time.Now()
+	}
+	from := time.Date(first.Year(), first.Month(), 1, 0, 0, 0, 0, time.Local)
+	to := time.Date(last.Year(), last.Month()+1, 0, 0, 0, 0, 0, time.Local)
+	return from, to
+}
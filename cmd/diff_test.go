@@ -0,0 +1,93 @@
+// Copyright © 2020 Philipp Galichkin
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/philgal/jtl/internal/config"
+	"github.com/philgal/jtl/internal/csv"
+	"github.com/philgal/jtl/internal/jira"
+	"github.com/philgal/jtl/internal/model"
+	"github.com/philgal/jtl/internal/reconcile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintDiff(t *testing.T) {
+	started := time.Date(2020, 4, 14, 11, 30, 0, 0, time.Local).Format(config.JiraDateTimePattern)
+	pushed := csv.Record{ID: "1", StartedTs: "14 Apr 2020 11:30", Comment: "Row with ID", TimeSpent: "10m", Ticket: "TICKET-1"}
+	worklog := func(id, comment string) string {
+		return fmt.Sprintf(`{"id":%q,"author":{"name":"me","key":"me"},"started":%q,"timeSpentSeconds":600,"comment":%q}`, id, started, comment)
+	}
+	newClient := func(t *testing.T, responses map[string]string) *jira.Client {
+		responses["GET /rest/api/2/myself"] = `{"name":"me","key":"me"}`
+		server := newFakeJiraServer(t, responses)
+		return jira.NewClient(server.URL, "2", &model.Credentials{Username: "u", Password: "p"}, server.Client())
+	}
+
+	t.Run("Should print differences and exit with status 1", func(t *testing.T) {
+		client := newClient(t, map[string]string{
+			"GET /rest/api/2/issue/TICKET-1/worklog/1": worklog("1", "changed in Jira"),
+			"GET /rest/api/2/search":                   `{"startAt":0,"total":1,"issues":[{"id":"10002","key":"TICKET-3"}]}`,
+			"GET /rest/api/2/issue/TICKET-3/worklog":   `{"startAt":0,"total":1,"worklogs":[` + worklog("200", "logged in Jira UI") + `]}`,
+		})
+		records := []csv.Record{pushed, {StartedTs: "15 Apr 2020 11:30", Comment: "Not pushed", TimeSpent: "10m", Ticket: "TICKET-2"}}
+		out := &bytes.Buffer{}
+
+		status, err := printDiff(out, client, records, false)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, status)
+		assert.Contains(t, out.String(), "changed in Jira")
+		assert.Contains(t, out.String(), "Not pushed")
+		assert.Contains(t, out.String(), "logged in Jira UI")
+		assert.Contains(t, out.String(), "MISSING: 0, CHANGED: 1, NOT PUSHED: 1, ONLY IN JIRA: 1")
+	})
+
+	t.Run("Should print differences as JSON", func(t *testing.T) {
+		client := newClient(t, map[string]string{
+			"GET /rest/api/2/search": `{"startAt":0,"total":0,"issues":[]}`,
+		})
+		out := &bytes.Buffer{}
+
+		status, err := printDiff(out, client, []csv.Record{pushed}, true)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, status)
+		result := reconcile.Result{}
+		assert.NoError(t, json.Unmarshal(out.Bytes(), &result))
+		assert.Len(t, result.MissingRemotely, 1)
+		assert.Equal(t, "1", result.MissingRemotely[0].ID)
+	})
+
+	t.Run("Should exit with status 0 when in sync", func(t *testing.T) {
+		client := newClient(t, map[string]string{
+			"GET /rest/api/2/issue/TICKET-1/worklog/1": worklog("1", "Row with ID"),
+			"GET /rest/api/2/search":                   `{"startAt":0,"total":1,"issues":[{"id":"10001","key":"TICKET-1"}]}`,
+			"GET /rest/api/2/issue/TICKET-1/worklog":   `{"startAt":0,"total":1,"worklogs":[` + worklog("1", "Row with ID") + `]}`,
+		})
+		out := &bytes.Buffer{}
+
+		status, err := printDiff(out, client, []csv.Record{pushed}, false)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, status)
+		assert.Equal(t, "Data file is in sync with Jira\n", out.String())
+	})
+}
@@ -0,0 +1,188 @@
+// Copyright © 2020 Philipp Galichkin
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"slices"
+
+	"github.com/jedib0t/go-pretty/table"
+	"github.com/philgal/jtl/internal/config"
+	"github.com/philgal/jtl/internal/credentials"
+	"github.com/philgal/jtl/internal/csv"
+	"github.com/philgal/jtl/internal/issues"
+	"github.com/philgal/jtl/internal/model"
+	"github.com/philgal/jtl/internal/rest"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// issuesCmd represents the issues command
+var issuesCmd = &cobra.Command{
+	Use:   "issues",
+	Short: "Manages local cache of Jira issues",
+	Long: `Jira issues of the data file are cached in ~/.jtl/cache/issues.json with their summary, status, project, epic and components.
+The cache is refreshed by push, pull and 'jtl issues refresh', for issues fetched more than <issues.ttl> ago.
+Reports show summaries of cached issues, and shell completion of 'jtl log' suggests recently used ones.
+Epic is read from the parent issue, or from <issues.epicField>, e.g. Epic Link custom field of Jira Server.
+Setting <issues.ttl> to 0 disables the cache.
+
+  issues:
+    ttl: 24h
+    epicField: customfield_10008
+`,
+}
+
+var issuesRefreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "Fetches stale issues of the data file and the cache from Jira",
+	Run: func(cmd *cobra.Command, args []string) {
+		cache := loadIssueCache()
+		if cache == nil {
+			fmt.Println("Issue cache is disabled by issues.ttl")
+			os.Exit(1)
+		}
+		csvFile := csv.NewCsvFile(config.DataFilePath())
+		csvFile.ReadAll()
+		keys := append(recordTickets(csvFile.Records), cache.Keys()...)
+		if force, _ := cmd.Flags().GetBool("force"); !force {
+			keys = cache.Stale(keys)
+		}
+		client := newJiraClient(resolveCredentials(credentials.Default()), rest.HTTPClient)
+		refreshed, err := cache.Refresh(client, keys, viper.GetString("issues.epicField"))
+		saveIssueCache(cache)
+		fmt.Printf("Refreshed %v issue(s)\n", refreshed)
+		if err != nil {
+			fmt.Println("Some issues were not refreshed:", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var issuesLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "Lists cached issues, most recently used first",
+	Run: func(cmd *cobra.Command, args []string) {
+		cache := loadIssueCache()
+		if cache == nil {
+			fmt.Println("Issue cache is disabled by issues.ttl")
+			os.Exit(1)
+		}
+		t := table.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		t.AppendHeader(table.Row{"ticket", "summary", "status", "project", "epic", "components", "fetched at"})
+		for _, issue := range cache.Recent(len(cache.Issues)) {
+			fetchedAt := "-"
+			if !issue.FetchedAt.IsZero() {
+				fetchedAt = issue.FetchedAt.Local().Format(config.DefaultDateTimePattern)
+			}
+			t.AppendRow(table.Row{issue.Key, issue.Summary, issue.Status, issue.Project, issue.Epic, issue.Components, fetchedAt})
+		}
+		t.Render()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(issuesCmd)
+	issuesCmd.AddCommand(issuesRefreshCmd, issuesLsCmd)
+	issuesRefreshCmd.Flags().BoolP("force", "f", false, "Refresh all issues, not only stale ones")
+}
+
+// loadIssueCache loads the issue cache, nil if it's disabled or can't be read
+func loadIssueCache() *issues.Cache {
+	ttl := viper.GetDuration("issues.ttl")
+	if ttl <= 0 {
+		return nil
+	}
+	cache, err := issues.Load(issues.DefaultPath(), ttl)
+	if err != nil {
+		log.Println("Error reading issue cache:", err)
+		return nil
+	}
+	return cache
+}
+
+func saveIssueCache(cache *issues.Cache) {
+	if err := cache.Save(); err != nil {
+		fmt.Println("Error saving issue cache:", err)
+		log.Println("Error saving issue cache:", err)
+	}
+}
+
+// refreshIssueCache fetches stale issues of the tickets, errors are only logged, as the cache is not essential
+func refreshIssueCache(fetcher issues.Fetcher, tickets []string) {
+	cache := loadIssueCache()
+	if cache == nil {
+		return
+	}
+	stale := cache.Stale(tickets)
+	if len(stale) == 0 {
+		return
+	}
+	if _, err := cache.Refresh(fetcher, stale, viper.GetString("issues.epicField")); err != nil {
+		log.Println("Error refreshing issue cache:", err)
+	}
+	saveIssueCache(cache)
+}
+
+// touchIssues marks the tickets as recently used
+func touchIssues(tickets ...string) {
+	if cache := loadIssueCache(); cache != nil {
+		cache.Touch(tickets...)
+		saveIssueCache(cache)
+	}
+}
+
+// issueSummaries returns summaries of cached issues, nil if the cache is disabled
+func issueSummaries() map[string]string {
+	if cache := loadIssueCache(); cache != nil {
+		return cache.Summaries()
+	}
+	return nil
+}
+
+// recordTickets returns distinct tickets of the records
+func recordTickets(records []csv.Record) []string {
+	var tickets []string
+	for _, r := range records {
+		if !slices.Contains(tickets, r.Ticket) {
+			tickets = append(tickets, r.Ticket)
+		}
+	}
+	return tickets
+}
+
+// completeRecentIssues suggests recently used issues with their summaries, and aliases with their tickets
+func completeRecentIssues(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var suggestions []string
+	aliases, _ := model.Aliases()
+	for _, name := range model.AliasNames(aliases) {
+		suggestions = append(suggestions, name+"\t"+aliases[name].Ticket)
+	}
+	if cache := loadIssueCache(); cache != nil {
+		for _, issue := range cache.Recent(recentIssuesLimit) {
+			suggestions = append(suggestions, issue.Key+"\t"+issue.Summary)
+		}
+	}
+	return suggestions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// recentIssuesLimit is a number of recently used issues suggested by completion
+const recentIssuesLimit = 20
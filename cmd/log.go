@@ -15,7 +15,12 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/philgal/jtl/internal/config"
+	"github.com/philgal/jtl/internal/dates"
+	"github.com/philgal/jtl/internal/jira"
 	"github.com/philgal/jtl/internal/log"
 	"github.com/philgal/jtl/internal/model"
 	"github.com/spf13/cobra"
@@ -29,6 +34,8 @@ var (
 	comment     string
 	startedTs   string
 	autoFitting bool
+	visibility  string
+	pick        bool
 )
 
 const (
@@ -44,8 +51,9 @@ var logCmd = &cobra.Command{
 	Short: "Adds Jira work log occurrence into a data file",
 	Long: `
 Adds Jira work log occurrence into a data file. Currently, the file is in a CSV format, so it can easily be edited manually before being pushed to a remote Jira <host>.
-To save yourself some typing, you can create ticket aliases in a config. Then these aliases can be used instead of ticket ids in the log command with -j flag.
-Ticket values specified in a config will the be logged and pushed.
+To save yourself some typing, you can create ticket aliases in a config, or with 'jtl alias add'. Then these aliases can be used instead of ticket ids in the log command.
+Ticket values specified in a config will the be logged and pushed. An alias can also set a default comment, time spent, visibility and estimate adjustment of its records,
+used unless they are set by flags.
 
   -----------------------
   %HOME%/.jtl/config.yaml
@@ -53,34 +61,110 @@ Ticket values specified in a config will the be logged and pushed.
   alias:
     jt1: JIRATICKET-1
     l666: ANOTHERLONGTICKET-666
+    standup:
+      ticket: MEET-7
+      comment: Daily standup
+      time: 15m
+      visibility: role:Developers
+      estimate: leave
   -----------------------
 
+Worklogs can be restricted to a role or a group with --visibility, e.g. role:Developers or group:jira-users.
+If it's not set, the visibility configured for the ticket's project is used:
+
+  visibility:
+    projects:
+      SUP: role:Developers
+
+Date and time of -d is either "14 Apr 2020 10:00", an ISO 8601 timestamp, or an expression relative to today:
+a day (today, yesterday, mon, -2d, -1w), a time of today (14:00, 9am), or both (yesterday 17:00, "mon 9:30").
+A day without the time starts at <schedule.dayStart>, 8:45 by default.
+
+Auto-fitting (-f) fits records of the day to its working hours in the schedule, 8h from Monday to Friday by default.
+Records of days off are logged as is. The length of 1d in durations is <schedule.day>:
+
+  schedule:
+    dayStart: "9:00"
+    day: 6h
+    hours:
+      fri: 0
+
+With --pick, the ticket is picked from issues found by the JQL query <pick.jql>, see 'jtl pick'.
+Shell completion suggests aliases and recently used issues with their summaries from the issue cache, see 'jtl issues'.
+
 Examples:
-  jtl log -j JIRA-101 -t 30m -s "14 Apr 2020 10:00" -m "Comment"
-  jtl log -j l666 -t 1h -s "06 Jun 2020 06:00" -m "Some repeating meeting!"
+  jtl log JIRA-101 -t 30m -d "14 Apr 2020 10:00" -m "Comment"
+  jtl log JIRA-101 -t 1h -d "-2d 14:00"
+  jtl log --pick -t 2h -m "Code review"
+  jtl log l666 -t 1h -d "06 Jun 2020 06:00" -m "Some repeating meeting!"
+  jtl log standup -d "06 Jun 2020 09:30"
 `,
-	Args: cobra.MinimumNArgs(1),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if pick {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
+	ValidArgsFunction: completeRecentIssues,
 	Run: func(cmd *cobra.Command, args []string) {
-		ticket = args[0]
+		if pick {
+			ticket = pickTicket()
+		} else {
+			ticket = args[0]
+		}
+		if a, found := model.ResolveAlias(ticket); found {
+			ticket = a.Ticket
+			applyAliasDefaults(cmd, a)
+		}
 		model.ValidateJiraTicketFormat(ticket)
+		if visibility == "" {
+			visibility = model.DefaultVisibility(ticket)
+		}
+		v, err := jira.ParseVisibility(visibility)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		started, err := dates.Parse(startedTs)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
 		executorArgs := log.ExecutorArgs{
-			Ticket:    ticket,
-			TimeSpent: timeSpent,
-			Comment:   comment,
-			StartedTs: startedTs}
+			Ticket:     ticket,
+			TimeSpent:  timeSpent,
+			Comment:    comment,
+			StartedTs:  started.Format(config.DefaultDateTimePattern),
+			Visibility: v.String()}
 		if autoFitting {
 			log.AutoFitting{ExecutorArgs: executorArgs}.Execute()
 		} else {
 			log.Normal{ExecutorArgs: executorArgs}.Execute()
 		}
+		touchIssues(ticket)
 		displayReport()
 	},
 }
 
+// applyAliasDefaults sets comment, time spent and visibility of the alias, unless they are set by flags
+func applyAliasDefaults(cmd *cobra.Command, a model.Alias) {
+	if a.Comment != "" && !cmd.Flags().Changed(messageCmdStr) {
+		comment = a.Comment
+	}
+	if a.TimeSpent != "" && !cmd.Flags().Changed(timeCmdStr) {
+		timeSpent = a.TimeSpent
+	}
+	if a.Visibility != "" && visibility == "" {
+		visibility = a.Visibility
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(logCmd)
 	logCmd.Flags().StringVarP(&timeSpent, timeCmdStr, "t", config.DefaultTicketDuration, "[Required] Time spent. Default - 4h")
 	logCmd.Flags().StringVarP(&comment, messageCmdStr, "m", "wip", "Comment to the work log. Will be displayed in Jira. Default - \"wip\"")
-	logCmd.Flags().StringVarP(&startedTs, dateCmdStr, "d", config.DefaultDayStart, "Date and time when the work has been started. Default - 8:45")
-	logCmd.Flags().BoolVarP(&autoFitting, "auto-fitting", "f", true, "Auto-fittimg mode adjusts not pushed records to fit the maximum *daily* duration. If false - logs whatever the input is! Default - true")
+	logCmd.Flags().StringVarP(&startedTs, dateCmdStr, "d", "today", "Date and time when the work has been started, e.g. yesterday, \"mon 9:30\" or \"14 Apr 2020 10:00\". Default - today at schedule.dayStart")
+	logCmd.Flags().StringVar(&visibility, "visibility", "", "Restrict the worklog to a role or a group, e.g. role:Developers. Default - visibility of the ticket's project in config")
+	logCmd.Flags().BoolVar(&pick, "pick", false, "Pick the ticket from issues found by the JQL query pick.jql, instead of passing it as an argument")
+	logCmd.Flags().BoolVarP(&autoFitting, "auto-fitting", "f", true, "Auto-fittimg mode adjusts not pushed records to fit the working hours of the day in the schedule. If false - logs whatever the input is! Default - true")
 }
@@ -0,0 +1,61 @@
+// Copyright © 2020 Philipp Galichkin
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/philgal/jtl/internal/credentials"
+	"github.com/spf13/cobra"
+)
+
+// loginCmd represents the login command
+var loginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Saves Jira credentials into an encrypted local store",
+	Long: `Prompts for the credentials required by <auth.type> and saves them into a store $HOME/.jtl/credentials.enc.
+The store is encrypted by a passphrase, which is asked on login and when the credentials are used. Without a terminal,
+e.g. in cron, the passphrase is read from JTL_PASSPHRASE.
+Username, if set in config.yaml, is not prompted. Stored credentials are used by 'jtl push', so no secret has to be kept in config.yaml.
+Use 'jtl logout' to remove them.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		store := credentials.NewFileStore(credentials.DefaultStorePath())
+		known, err := credentials.Config{}.Credentials()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		// don't keep plaintext secrets from config, they are prompted again
+		known.Password, known.Token = "", ""
+		creds, err := credentials.Prompt{In: os.Stdin, From: credentials.Static{Creds: *known}}.Credentials()
+		if err == nil && !creds.IsValid() {
+			err = fmt.Errorf("credentials required by %q auth are not complete", creds.AuthType())
+		}
+		if err == nil {
+			err = store.Save(creds)
+		}
+		if err != nil {
+			fmt.Println("Login failed:", err)
+			os.Exit(1)
+		}
+		fmt.Println("Credentials saved to", store.Path)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(loginCmd)
+}
@@ -0,0 +1,40 @@
+// Copyright © 2020 Philipp Galichkin
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/philgal/jtl/internal/credentials"
+	"github.com/spf13/cobra"
+)
+
+// logoutCmd represents the logout command
+var logoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Removes Jira credentials saved by 'jtl login'",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := credentials.NewFileStore(credentials.DefaultStorePath()).Clear(); err != nil {
+			fmt.Println("Logout failed:", err)
+			os.Exit(1)
+		}
+		fmt.Println("Stored credentials removed")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(logoutCmd)
+}
@@ -0,0 +1,91 @@
+// Copyright © 2020 Philipp Galichkin
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/philgal/jtl/internal/credentials"
+	"github.com/philgal/jtl/internal/jira"
+	"github.com/philgal/jtl/internal/picker"
+	"github.com/philgal/jtl/internal/rest"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// pickCmd represents the pick command
+var pickCmd = &cobra.Command{
+	Use:   "pick",
+	Short: "Picks a Jira ticket from results of a JQL query and prints its key",
+	Long: `Runs the JQL query <pick.jql> on Jira <host> and lets you pick one of found issues.
+In a terminal, issues are filtered as you type, arrows select an issue, Enter picks it and Esc cancels.
+Otherwise, issues are listed with numbers and the number of the picked one is read from stdin.
+The list is written to stderr, so the picked key can be used in other commands, e.g. jtl log $(jtl pick).
+
+  pick:
+    jql: assignee = currentUser() AND sprint in openSprints()
+
+Examples:
+  jtl pick
+  jtl pick --jql "project = SUP AND status = 'In Progress'"
+  jtl log --pick -t 1h -m "Review"
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println(pickTicket())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pickCmd)
+	pickCmd.Flags().String("jql", "", "JQL query of issues to pick from. Default - pick.jql from config")
+	viper.BindPFlag("pick.jql", pickCmd.Flags().Lookup("jql"))
+}
+
+// pickTicket searches issues with pick.jql and lets the user pick one, exits if nothing is picked
+func pickTicket() string {
+	client := newJiraClient(resolveCredentials(credentials.Default()), rest.HTTPClient)
+	issue, err := pickIssue(client, viper.GetString("pick.jql"), picker.Pick)
+	if errors.Is(err, picker.ErrCancelled) {
+		fmt.Fprintln(os.Stderr, "No ticket picked")
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Cannot pick a ticket:", err)
+		log.Fatalln("Cannot pick a ticket:", err)
+	}
+	return issue.Key
+}
+
+func pickIssue(client *jira.Client, jql string, pick func([]picker.Item) (picker.Item, error)) (picker.Item, error) {
+	if jql == "" {
+		return picker.Item{}, errors.New("pick.jql is not set")
+	}
+	found, err := client.SearchIssues(jql, "summary")
+	if err != nil {
+		return picker.Item{}, err
+	}
+	if len(found) == 0 {
+		return picker.Item{}, fmt.Errorf("no issues found by %q", jql)
+	}
+	items := make([]picker.Item, len(found))
+	for i, issue := range found {
+		items[i] = picker.Item{Key: issue.Key, Summary: issue.Fields.Summary}
+	}
+	return pick(items)
+}
@@ -0,0 +1,52 @@
+// Copyright © 2020 Philipp Galichkin
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/philgal/jtl/internal/jira"
+	"github.com/philgal/jtl/internal/model"
+	"github.com/philgal/jtl/internal/picker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPickIssue(t *testing.T) {
+	server := newFakeJiraServer(t, map[string]string{
+		"GET /rest/api/2/search": `{"startAt":0,"total":2,"issues":[
+			{"id":"1","key":"SUP-1","fields":{"summary":"Support request"}},
+			{"id":"2","key":"DEV-2","fields":{"summary":"Payment API"}}
+		]}`,
+	})
+	client := jira.NewClient(server.URL, "2", &model.Credentials{Username: "u", Password: "p"}, server.Client())
+
+	t.Run("Should pick from issues found by JQL with their summaries", func(t *testing.T) {
+		var offered []picker.Item
+		picked, err := pickIssue(client, "assignee = currentUser()", func(items []picker.Item) (picker.Item, error) {
+			offered = items
+			return items[1], nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "DEV-2", picked.Key)
+		assert.Equal(t, []picker.Item{{Key: "SUP-1", Summary: "Support request"}, {Key: "DEV-2", Summary: "Payment API"}}, offered)
+	})
+
+	t.Run("Should fail without JQL", func(t *testing.T) {
+		_, err := pickIssue(client, "", picker.Pick)
+
+		assert.ErrorContains(t, err, "pick.jql is not set")
+	})
+}
@@ -0,0 +1,139 @@
+// Copyright © 2020 Philipp Galichkin
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/philgal/jtl/internal/config"
+	"github.com/philgal/jtl/internal/credentials"
+	"github.com/philgal/jtl/internal/csv"
+	"github.com/philgal/jtl/internal/dates"
+	"github.com/philgal/jtl/internal/duration"
+	"github.com/philgal/jtl/internal/jira"
+	"github.com/philgal/jtl/internal/ledger"
+	"github.com/philgal/jtl/internal/rest"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// pullCmd represents the pull command
+var pullCmd = &cobra.Command{
+	Use:   "pull",
+	Short: "Pulls your worklogs from a remote Jira server into a data file",
+	Long: `Pulls worklogs of the current user, started in the --from/--to date range, from a Jira server defined as <host> in config.yaml.
+Worklogs are added into the data file as pushed records with their Jira IDs. Worklogs, whose IDs are already in the data file, are skipped.
+By default, worklogs are pulled from the first day of the current month till today.
+
+Examples:
+  jtl pull
+  jtl pull --from "01 Apr 2020" --to "15 Apr 2020"
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		from, to := dateRangeFlags(cmd)
+		// checked before credentials are resolved, so that an unconfigured setup doesn't prompt for a password
+		if viper.GetString("host") == "" {
+			fmt.Println("Jira host is not set in config")
+			os.Exit(1)
+		}
+		client := newJiraClient(resolveCredentials(credentials.Default()), rest.HTTPClient)
+		csvFile := csv.NewCsvFile(config.DataFilePath())
+		csvFile.ReadAll()
+		added, err := pull(client, &csvFile, from, to)
+		if err != nil {
+			fmt.Println("Pull failed:", err)
+			log.Fatalln("Pull failed:", err)
+		}
+		fmt.Printf("Pulled %v new worklog(s)\n", added)
+		refreshIssueCache(client, recordTickets(csvFile.Records))
+		displayReport()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pullCmd)
+	now := time.Now()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	pullCmd.Flags().String("from", monthStart.Format(config.DefaultDatePattern), "First day of the range, e.g. \"01 Apr 2020\". Default - first day of the current month")
+	pullCmd.Flags().String("to", now.Format(config.DefaultDatePattern), "Last day of the range, e.g. \"30 Apr 2020\". Default - today")
+}
+
+// dateRangeFlags parses --from and --to flags, exits if they are not valid
+func dateRangeFlags(cmd *cobra.Command) (time.Time, time.Time) {
+	parse := func(name string) time.Time {
+		value, _ := cmd.Flags().GetString(name)
+		t, err := dates.ParseDay(value)
+		if err != nil {
+			fmt.Printf("Invalid --%v: %v\n", name, err)
+			os.Exit(1)
+		}
+		return t
+	}
+	from, to := parse("from"), parse("to")
+	if to.Before(from) {
+		fmt.Println("--to date must not be before --from date")
+		os.Exit(1)
+	}
+	return from, to
+}
+
+// pull adds the current user's worklogs from the date range, which are not in the file yet. Returns the number of added records.
+func pull(client *jira.Client, csvFile *csv.File, from, to time.Time) (int, error) {
+	if viper.GetString("host") == "" {
+		return 0, fmt.Errorf("jira host is not set in config")
+	}
+	me, err := client.Myself()
+	if err != nil {
+		return 0, fmt.Errorf("cannot resolve current user: %w", err)
+	}
+	worklogs, err := client.UserWorklogs(me, from, to)
+	if err != nil {
+		return 0, err
+	}
+	known := map[string]bool{}
+	for _, r := range csvFile.Records {
+		if r.IsPushed() {
+			known[r.ID] = true
+		}
+	}
+	var pulled []csv.Record
+	for _, w := range worklogs {
+		if !known[w.ID] {
+			known[w.ID] = true
+			pulled = append(pulled, jira.RecordFromWorklog(w))
+		}
+	}
+	slices.SortStableFunc(pulled, func(a, b csv.Record) int {
+		return duration.ParseTime(a.StartedTs).Compare(duration.ParseTime(b.StartedTs))
+	})
+	if len(pulled) == 0 {
+		return 0, nil
+	}
+	pullLedger, err := ledger.Load(ledger.PathFor(csvFile.Path))
+	if err != nil {
+		return 0, err
+	}
+	for _, r := range pulled {
+		csvFile.AddRecord(r)
+		pullLedger.Track(r)
+		log.Printf("Pulled worklog %v of %v\n", r.ID, r.Ticket)
+	}
+	csvFile.Write()
+	return len(pulled), pullLedger.Save()
+}
@@ -0,0 +1,84 @@
+// Copyright © 2020 Philipp Galichkin
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/philgal/jtl/internal/csv"
+	"github.com/philgal/jtl/internal/jira"
+	"github.com/philgal/jtl/internal/model"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// newFakeJiraServer serves canned JSON responses by request path
+func newFakeJiraServer(t *testing.T, responses map[string]string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, found := responses[r.Method+" "+r.URL.Path]
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"errorMessages":["Not found"]}`)
+			return
+		}
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(server.Close)
+	viper.Set("host", server.URL)
+	t.Cleanup(viper.Reset)
+	return server
+}
+
+func TestPull(t *testing.T) {
+	server := newFakeJiraServer(t, map[string]string{
+		"GET /rest/api/2/myself": `{"name":"me","key":"me"}`,
+		"GET /rest/api/2/search": `{"startAt":0,"total":1,"issues":[{"id":"10002","key":"TICKET-3"}]}`,
+		"GET /rest/api/2/issue/TICKET-3/worklog": `{"startAt":0,"total":3,"worklogs":[
+			{"id":"1","author":{"name":"me","key":"me"},"started":"2020-04-14T11:30:00.000+0000","timeSpentSeconds":600,"comment":"already known"},
+			{"id":"200","author":{"name":"me","key":"me"},"started":"2020-04-16T09:00:00.000+0000","timeSpentSeconds":5400,"comment":"logged in Jira UI"},
+			{"id":"300","author":{"name":"teammate","key":"teammate"},"started":"2020-04-16T09:00:00.000+0000","timeSpentSeconds":3600,"comment":"not mine"}
+		]}`,
+	})
+	dataFile := setupDataFile(t)
+	csvFile := csv.NewCsvFile(dataFile)
+	csvFile.ReadAll()
+	client := jira.NewClient(server.URL, "2", &model.Credentials{Username: "u", Password: "p"}, server.Client())
+
+	t.Run("Should add only missing worklogs of the current user", func(t *testing.T) {
+		added, err := pull(client, &csvFile, time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC), time.Date(2020, 4, 30, 0, 0, 0, 0, time.UTC))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, added)
+		written := csv.NewCsvFile(dataFile)
+		written.ReadAll()
+		assert.Len(t, written.Records, 3)
+		pulled := written.Records[2]
+		assert.Equal(t, "200", pulled.ID)
+		assert.Equal(t, "TICKET-3", pulled.Ticket)
+		assert.Equal(t, "1h 30m", pulled.TimeSpent)
+		assert.Equal(t, "logged in Jira UI", pulled.Comment)
+	})
+
+	t.Run("Should skip worklogs pulled before", func(t *testing.T) {
+		added, err := pull(client, &csvFile, time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC), time.Date(2020, 4, 30, 0, 0, 0, 0, time.UTC))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, added)
+	})
+}
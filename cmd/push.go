@@ -16,23 +16,31 @@ package cmd
 
 import (
 	"bufio"
-	"bytes"
-	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
 	"os"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/philgal/jtl/internal/config"
+	"github.com/philgal/jtl/internal/credentials"
 	"github.com/philgal/jtl/internal/csv"
+	"github.com/philgal/jtl/internal/dates"
+	"github.com/philgal/jtl/internal/jira"
+	"github.com/philgal/jtl/internal/ledger"
 	"github.com/philgal/jtl/internal/model"
+	"github.com/philgal/jtl/internal/picker"
+	"github.com/philgal/jtl/internal/report"
 	"github.com/philgal/jtl/internal/rest"
+	"github.com/philgal/jtl/internal/target"
+	"github.com/philgal/jtl/internal/tempo"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"golang.org/x/term"
 )
@@ -42,189 +50,683 @@ var pushCmd = &cobra.Command{
 	Use:   "push",
 	Short: "Pushes data to a remote Jira server defined as <host> in config.yaml.",
 	Long: `Pushes data to a remote server defined as <host> in config.yaml.
-For correct work this command reqiures a configured <host> and user <credentials> in the config.yaml:
+New records are created as worklogs, edited ones are updated, and worklogs of removed records are deleted once confirmed.
+Pushed records are tracked in a ledger next to the data file, <data file>.ledger.json.
 
-  host: https://jira.server.url
-  credentials:
-    username: <username>
-    password: <password>
+Credentials are read from config.yaml, the output of <credentials.passwordCommand> or the store written by 'jtl login'.
+Missing ones are prompted. Tickets are validated before the push, which is confirmed with a prompt, or by --yes without a terminal.
+Records can be pushed to Tempo and custom HTTP targets with <push.target> or --target.
+The command exits with a non-zero code if the push is cancelled or any of the records failed.
 
-However, if username and password are not defined, a user will be prompted to enter them.
+See README.md for authentication, remaining estimate, visibility, push targets, concurrency and retries,
+and example-config.yaml for their settings.
 
-Preview mode:
-To make sure the data to be pushed is correct, the command can be executed with -p flag.
-The preview output contains host, username and prepared requests bodies for POST request to Jira.
+Examples:
+  jtl push -p
+  jtl push --before today --project SUP
+  jtl push -i
+  jtl push --yes --delete-removed
+  jtl push --target jira,billing
 	`,
 	Run: func(cmd *cobra.Command, args []string) {
-		PushToServer(cmd)
+		if !PushToServer(cmd) {
+			os.Exit(1)
+		}
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(pushCmd)
 	pushCmd.Flags().BoolP("preview", "p", false, "Preview request to be sent to Jira server")
+	pushCmd.Flags().BoolP("yes", "y", false, "Push without confirming the tickets to push to")
+	pushCmd.Flags().Bool("delete-removed", false, "Delete worklogs of records removed from the data file without confirmation")
+	pushCmd.Flags().StringSlice("target", nil, "Targets to push to, the first one is primary, e.g. --target jira,billing. Default - push.target from config or jira")
+	viper.BindPFlag("push.target", pushCmd.Flags().Lookup("target"))
+	addPushFilterFlags(pushCmd.Flags())
+	pushCmd.Flags().String("adjust-estimate", "", "Adjust remaining estimate of new worklogs, overriding config rules: auto, leave, new:<estimate> or manual:<reduce by>")
 }
 
-const jiraURLTemplate = "/rest/api/2/issue/%v/worklog"
-
-var creds model.Credentials
-
-// PushToServer reads report data and logs work on jira server
-func PushToServer(cmd *cobra.Command) {
-	push(cmd, rest.HTTPClient)
+// PushToServer reads report data and logs work on jira server.
+// Returns false if the push is cancelled or any of the records failed to be pushed.
+func PushToServer(cmd *cobra.Command) bool {
+	resp, err := push(cmd, rest.HTTPClient, credentials.Default())
+	if err != nil {
+		fmt.Println(err)
+		return false
+	}
 	displayReport()
+	if len(resp) > 0 {
+		pushReport := report.NewPushReport(resp)
+		pushReport.Print()
+		return !pushReport.HasFailures()
+	}
+	return true
 }
 
-func push(cmd *cobra.Command, restClient rest.Client) {
+// errPushCancelled is returned if the user doesn't confirm the push or it can't be confirmed without a terminal
+var errPushCancelled = errors.New("push cancelled")
+
+func push(cmd *cobra.Command, restClient rest.Client, credProvider credentials.Provider) ([]model.JiraResponse, error) {
 	csvFile := csv.NewCsvFile(config.DataFilePath())
 	csvFile.ReadAll()
-	jreq := model.NewJiraRequest(csvFile.Records)
+	if resolved := model.ResolveRecordAliases(csvFile.Records); resolved > 0 {
+		log.Printf("Resolved aliases of %v record(s)\n", resolved)
+	}
+	pushLedger, err := ledger.Load(ledger.PathFor(csvFile.Path))
+	if err != nil {
+		fmt.Println("Error reading ledger of pushed records:", err)
+		log.Fatalln("Error reading ledger of pushed records:", err)
+	}
+	filter, err := pushFilter(cmd)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	jreq := append(model.NewJiraRequest(csvFile.Records, filter), model.NewJiraSyncRequest(csvFile.Records, pushLedger, filter)...)
+	if err := applyEstimateAdjustment(cmd, jreq); err != nil {
+		fmt.Println("Error reading estimate adjustment:", err)
+		os.Exit(1)
+	}
+	interactive, _ := cmd.Flags().GetBool("interactive")
+	if interactive && len(jreq) > 0 {
+		if jreq, err = selectRequests(jreq, picker.Check); err != nil {
+			return nil, fmt.Errorf("%w: %v", errPushCancelled, err)
+		}
+		filter = filter.Tick(jreq)
+	}
+	targets := pushTargetNames()
 
-	if viper.GetString("Host") == "" {
+	if viper.GetString("Host") == "" && needsJira(targets) {
 		fmt.Println("Jira host is not set in config, printing preview")
-		preview(jreq)
-		return
+		preview(targets, jreq, csvFile, filter, credProvider)
+		return nil, nil
 	}
 
 	if shouldPreview, _ := cmd.Flags().GetBool("preview"); shouldPreview {
-		preview(jreq)
-		return
+		preview(targets, jreq, csvFile, filter, credProvider)
+		return nil, nil
 	}
 
-	resp := post(readCredentials(), jreq, restClient)
-	updatePushedRecordsIds(resp, csvFile.Records)
+	// ticking a deletion confirms it
+	if deleteRemoved, _ := cmd.Flags().GetBool("delete-removed"); !deleteRemoved && !interactive {
+		jreq, filter = confirmDeletions(jreq, filter, promptDeletions)
+	}
+
+	var cred *model.Credentials
+	var skipped []model.JiraResponse
+	if needsJira(targets) {
+		cred = resolveCredentials(credProvider)
+		if viper.GetBool("push.validate") {
+			confirm := promptConfirmation
+			if yes, _ := cmd.Flags().GetBool("yes"); yes {
+				confirm = func(int) bool { return true }
+			}
+			var proceed bool
+			if jreq, skipped, proceed = validateTickets(newJiraClient(cred, restClient), jreq, confirm); !proceed {
+				return nil, errPushCancelled
+			}
+		}
+	}
+	// every successful response is saved right away, so a crash in the middle of push doesn't lose pushed IDs
+	resp := post(cred, jreq, restClient, csvFile.Records, func(r model.JiraResponse) {
+		if !r.IsSuccess {
+			return
+		}
+		updatePushedRecordsIds([]model.JiraResponse{r}, csvFile.Records)
+		csvFile.Write()
+		updateLedger(pushLedger, r, csvFile.Records)
+	})
 	log.Printf("CSV records, updated after push: %q\n", csvFile.Records)
-	csvFile.Write()
+	trackUntrackedRecords(pushLedger, csvFile.Records)
+	resp = append(resp, skipped...)
+	for _, name := range targets[1:] {
+		resp = append(resp, mirror(name, cred, restClient, csvFile, filter)...)
+	}
+	if cred != nil {
+		refreshIssueCache(newJiraClient(cred, restClient), recordTickets(csvFile.Records))
+	}
+	return resp, nil
+}
+
+func addPushFilterFlags(flags *pflag.FlagSet) {
+	flags.String("date", "", "Push only records of the day, e.g. \"14 Apr 2020\", today or yesterday")
+	flags.String("from", "", "Push only records from the day, inclusive")
+	flags.String("to", "", "Push only records till the day, inclusive")
+	flags.String("before", "", "Push only records started before the day, e.g. --before today")
+	flags.StringSlice("ticket", nil, "Push only records of the tickets or aliases, e.g. --ticket JIRA-1,jt1")
+	flags.StringSlice("project", nil, "Push only records of the projects, e.g. --project SUP")
+	flags.BoolP("interactive", "i", false, "Tick records to push from the list of selected ones")
+}
+
+// pushFilter reads filters of records to push from flags
+func pushFilter(cmd *cobra.Command) (model.RecordFilter, error) {
+	var filter model.RecordFilter
+	day := func(name string) (time.Time, error) {
+		value, _ := cmd.Flags().GetString(name)
+		if value == "" {
+			return time.Time{}, nil
+		}
+		t, err := dates.ParseDay(value)
+		if err != nil {
+			return t, fmt.Errorf("invalid --%v: %w", name, err)
+		}
+		return t, nil
+	}
+	var err error
+	if filter.From, err = day("from"); err != nil {
+		return filter, err
+	}
+	if filter.To, err = day("to"); err != nil {
+		return filter, err
+	}
+	if filter.Before, err = day("before"); err != nil {
+		return filter, err
+	}
+	date, err := day("date")
+	if err != nil {
+		return filter, err
+	}
+	if !date.IsZero() {
+		if !filter.From.IsZero() || !filter.To.IsZero() {
+			return filter, errors.New("--date can't be combined with --from and --to")
+		}
+		filter.From, filter.To = date, date
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		return filter, errors.New("--to date must not be before --from date")
+	}
+	tickets, _ := cmd.Flags().GetStringSlice("ticket")
+	filter.Tickets = model.ResolveTickets(tickets)
+	filter.Projects, _ = cmd.Flags().GetStringSlice("project")
+	return filter, nil
 }
 
-func preview(jr model.JiraRequest) {
+// selectRequests lets the user tick requests to push
+func selectRequests(jreq model.JiraRequest, check func(lines []string) ([]int, error)) (model.JiraRequest, error) {
+	lines := make([]string, len(jreq))
+	for i, row := range jreq {
+		lines[i] = fmt.Sprintf("%-6v %-17v %-12v %-8v %v", row.Method, row.Started, row.Jiraticket, row.Timespent, row.Comment)
+	}
+	ticked, err := check(lines)
+	if err != nil {
+		return nil, err
+	}
+	selected := model.JiraRequest{}
+	for _, i := range ticked {
+		selected = append(selected, jreq[i])
+	}
+	return selected, nil
+}
+
+// validateTickets checks that tickets of created and updated worklogs exist, are not closed and accept the user's worklogs.
+// Requests to invalid tickets are skipped and returned as failed responses. Returns false if there is nothing to push or the user doesn't confirm it.
+func validateTickets(client *jira.Client, jreq model.JiraRequest, confirm func(requests int) bool) (model.JiraRequest, []model.JiraResponse, bool) {
+	var keys []string
+	worklogs := map[string]int{}
+	for _, row := range jreq {
+		if row.Method != http.MethodDelete {
+			keys = append(keys, row.Jiraticket)
+			worklogs[row.Jiraticket]++
+		}
+	}
+	if len(keys) == 0 {
+		return jreq, nil, true
+	}
+	checks := client.CheckIssues(keys, viper.GetStringSlice("push.closedStatuses"))
+	report.NewIssueCheckReport(checks, worklogs).Print()
+	problems := map[string]string{}
+	for _, c := range checks {
+		if !c.IsValid() {
+			problems[c.Key] = c.Problem
+		}
+	}
+	valid := model.JiraRequest{}
+	var skipped []model.JiraResponse
+	for _, row := range jreq {
+		problem, found := problems[row.Jiraticket]
+		if !found || row.Method == http.MethodDelete {
+			valid = append(valid, row)
+			continue
+		}
+		skipped = append(skipped, model.JiraResponse{
+			Target:    pushTargetNames()[0],
+			RowIdx:    row.GetIdx(),
+			Method:    row.Method,
+			Ticket:    row.Jiraticket,
+			Timespent: row.Timespent,
+			Comment:   row.Comment,
+			Started:   row.Started,
+			Err:       fmt.Errorf("skipped: %v", problem),
+		})
+	}
+	if len(valid) == 0 {
+		fmt.Println("Nothing to push to valid tickets")
+		return valid, skipped, len(skipped) > 0
+	}
+	return valid, skipped, confirm(len(valid))
+}
+
+// promptConfirmation asks the user to confirm the push, refusing it if stdin is not a terminal
+func promptConfirmation(requests int) bool {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Println("Stdin is not a terminal, run with --yes to push without confirmation")
+		return false
+	}
+	fmt.Printf("Push %v request(s)? [y/N]: ", requests)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// confirmDeletions asks the user to confirm deletion of worklogs of records removed from the data file.
+// If refused, deletions are dropped from the requests and excluded by the returned filter, so they are not mirrored either.
+func confirmDeletions(jreq model.JiraRequest, filter model.RecordFilter, confirm func(deletions model.JiraRequest) bool) (model.JiraRequest, model.RecordFilter) {
+	kept := model.JiraRequest{}
+	deletions := model.JiraRequest{}
+	for _, row := range jreq {
+		if row.Method == http.MethodDelete {
+			deletions = append(deletions, row)
+		} else {
+			kept = append(kept, row)
+		}
+	}
+	if len(deletions) == 0 || confirm(deletions) {
+		return jreq, filter
+	}
+	filter.SkipRemoved = true
+	return kept, filter
+}
+
+// promptDeletions lists worklogs to delete and asks the user to confirm it, refusing it if stdin is not a terminal
+func promptDeletions(deletions model.JiraRequest) bool {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Printf("Not deleting %v worklog(s) of records removed from the data file, run with --delete-removed to delete them\n", len(deletions))
+		return false
+	}
+	fmt.Println("Records removed from the data file:")
+	for _, row := range deletions {
+		fmt.Printf("  %-12v worklog %v\n", row.Jiraticket, row.WorklogID)
+	}
+	fmt.Printf("Delete their %v worklog(s)? [y/N]: ", len(deletions))
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// mirror pushes records, pushed to the primary target, to another target, keeping IDs of its worklogs in the target's ledger
+func mirror(name string, cred *model.Credentials, restClient rest.Client, csvFile csv.File, filter model.RecordFilter) []model.JiraResponse {
+	mirrorLedger, err := ledger.Load(ledger.PathForTarget(csvFile.Path, name))
+	if err != nil {
+		fmt.Printf("Error reading ledger of records pushed to %v: %v\n", name, err)
+		log.Fatalf("Error reading ledger of records pushed to %v: %v\n", name, err)
+	}
+	jreq := model.NewJiraMirrorRequest(csvFile.Records, mirrorLedger, filter)
+	t, err := newPushTarget(name, cred, rateLimited(restClient), jreq, csvFile.Records)
+	if err != nil {
+		fmt.Println("Error configuring push target:", err)
+		log.Fatalln("Error configuring push target:", err)
+	}
+	return sendAll(name, t, jreq, func(r model.JiraResponse) {
+		if !r.IsSuccess {
+			return
+		}
+		if r.Method == http.MethodDelete {
+			mirrorLedger.ForgetMirror(r.Id)
+		} else {
+			mirrorLedger.TrackMirror(csvFile.Records[r.RowIdx], r.Id)
+		}
+		saveLedger(mirrorLedger)
+	})
+}
+
+// applyEstimateAdjustment sets estimate adjustment of new worklogs from --adjust-estimate flag or, if it's not set, from estimate rules in config
+func applyEstimateAdjustment(cmd *cobra.Command, jreq model.JiraRequest) error {
+	rules, err := model.NewEstimateRules()
+	if err != nil {
+		return err
+	}
+	if flag, _ := cmd.Flags().GetString("adjust-estimate"); flag != "" {
+		override, err := model.ParseEstimateAdjustment(flag)
+		if err != nil {
+			return err
+		}
+		rules = model.EstimateRules{Default: override}
+	}
+	for i := range jreq {
+		if jreq[i].Method == http.MethodPost {
+			jreq[i].Estimate = rules.For(jreq[i].Jiraticket)
+		}
+	}
+	return nil
+}
+
+// updateLedger tracks the content of created and updated records, forgets deleted ones
+func updateLedger(l *ledger.Ledger, r model.JiraResponse, csvRecords []csv.Record) {
+	if r.Method == http.MethodDelete {
+		l.Forget(r.Id)
+	} else {
+		l.Track(csvRecords[r.RowIdx])
+	}
+	saveLedger(l)
+}
+
+// trackUntrackedRecords tracks pushed records, unknown to the ledger (pushed by an older version or pulled), as they are now
+func trackUntrackedRecords(l *ledger.Ledger, csvRecords []csv.Record) {
+	for _, rec := range csvRecords {
+		if rec.IsPushed() && !l.IsTracked(rec) {
+			l.Track(rec)
+		}
+	}
+	saveLedger(l)
+}
+
+func saveLedger(l *ledger.Ledger) {
+	if err := l.Save(); err != nil {
+		fmt.Println("Error saving ledger of pushed records:", err)
+		log.Println("Error saving ledger of pushed records:", err)
+	}
+}
+
+func preview(targets []string, jr model.JiraRequest, csvFile csv.File, filter model.RecordFilter, credProvider credentials.Provider) {
 	fmt.Printf("------------\n%v\n------------\n", "PREVIEW MODE")
-	fmt.Printf("Jira server: %v\n", viper.GetString("host"))
-	fmt.Println("User:", readCredentials().Username)
-	for _, row := range jr {
-		fmt.Println()
-		fmt.Println("POST", buildPostURL(row.Jiraticket))
-		fmt.Println(jsonBodyStr(&row))
-		fmt.Println()
-	}
-	fmt.Printf("Total requests: %v\n", len(jr))
+	var previewCreds *model.Credentials
+	if needsJira(targets) {
+		fmt.Printf("Jira server: %v\n", viper.GetString("host"))
+		previewCreds = resolveCredentials(credProvider)
+		fmt.Println("Auth:", previewCreds.AuthType())
+		fmt.Println("User:", previewCreds.Username)
+	}
+	total := 0
+	for i, name := range targets {
+		rows := jr
+		if i > 0 {
+			mirrorLedger, err := ledger.Load(ledger.PathForTarget(csvFile.Path, name))
+			if err != nil {
+				fmt.Println(err)
+				continue
+			}
+			// records, pushed to the primary target by this push, are not included
+			rows = model.NewJiraMirrorRequest(csvFile.Records, mirrorLedger, filter)
+		}
+		fmt.Println("Target:", name)
+		t, err := newPushTarget(name, previewCreds, nil, nil, nil)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		for _, row := range rows {
+			fmt.Println()
+			method, url, body, err := t.Preview(row)
+			fmt.Println(method, url)
+			if err != nil {
+				fmt.Println(err)
+				continue
+			}
+			if body != nil {
+				jsonBody, _ := json.Marshal(body)
+				fmt.Println(string(jsonBody))
+			}
+			fmt.Println()
+		}
+		total += len(rows)
+	}
+	fmt.Printf("Total requests: %v\n", total)
 	fmt.Printf("-----\n%v\n-----\n", "Done!")
 }
 
-func post(cred *model.Credentials, jiraReq model.JiraRequest, restClient rest.Client) []model.JiraResponse {
-	responses := []model.JiraResponse{}
-	respChn := make(chan model.JiraResponse, len(jiraReq))
+// post sends the requests to the primary push target with a pool of workers.
+// onResponse, if set, is called for each response as soon as it's received, one at a time.
+func post(cred *model.Credentials, jiraReq model.JiraRequest, restClient rest.Client, csvRecords []csv.Record, onResponse func(model.JiraResponse)) []model.JiraResponse {
+	name := pushTargetNames()[0]
+	t, err := newPushTarget(name, cred, rateLimited(restClient), jiraReq, csvRecords)
+	if err != nil {
+		fmt.Println("Error configuring push target:", err)
+		log.Fatalln("Error configuring push target:", err)
+	}
+	return sendAll(name, t, jiraReq, onResponse)
+}
+
+// rateLimited limits the client to push.rateLimit requests per second
+func rateLimited(restClient rest.Client) rest.Client {
+	return rest.WithRateLimit(restClient, rest.NewRateLimiter(viper.GetFloat64("push.rateLimit")))
+}
+
+// sendAll sends the requests to the target with a pool of workers, onResponse is called for each response one at a time
+func sendAll(name string, t target.Target, jiraReq model.JiraRequest, onResponse func(model.JiraResponse)) []model.JiraResponse {
+	// responses are collected by request position, so they keep the order of the request rows
+	responses := make([]model.JiraResponse, len(jiraReq))
+	type indexedResponse struct {
+		idx  int
+		resp model.JiraResponse
+	}
+	jobs := make(chan int)
+	results := make(chan indexedResponse)
 	wg := sync.WaitGroup{}
-	for _, row := range jiraReq {
+	for range min(pushConcurrency(), len(jiraReq)) {
 		wg.Add(1)
-		go postSingleRequest(cred, row, restClient, respChn, &wg)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				resp := sendSingleRequest(t, jiraReq[i])
+				resp.Target = name
+				results <- indexedResponse{i, resp}
+			}
+		}()
 	}
-	wg.Wait()
-	close(respChn)
-	for resp := range respChn {
-		responses = append(responses, resp)
+	go func() {
+		for i := range jiraReq {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+	for r := range results {
+		responses[r.idx] = r.resp
+		if onResponse != nil {
+			onResponse(r.resp)
+		}
 	}
 	return responses
 }
 
-func postSingleRequest(cred *model.Credentials, row model.JiraRequestRow, restClient rest.Client, respChn chan model.JiraResponse, wg *sync.WaitGroup) {
-	req, _ := buildHTTPRequest(row.Jiraticket, cred, &row)
-	res, err := restClient.Do(req)
-	if err != nil {
-		log.Fatalf("Failed to send %v: %v\n", req, err)
-		fmt.Printf("Failed to send %v: %v\n", req, err)
+// pushTargetNames returns names of the targets to push to, the first one is primary. Jira is the default target.
+func pushTargetNames() []string {
+	var names []string
+	for _, name := range viper.GetStringSlice("push.target") {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
 	}
-	defer res.Body.Close()
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		log.Fatal(err)
+	if len(names) == 0 {
+		return []string{target.NameJira}
 	}
-	//if response was successful
-	jiraRes := model.JiraResponse{RowIdx: row.GetIdx(), IsSuccess: false}
-	if res.StatusCode == 201 {
-		//unmarshall response
-		err = json.Unmarshal(body, &jiraRes)
+	return names
+}
+
+// needsJira returns true if any of the targets sends requests to Jira
+func needsJira(targets []string) bool {
+	return slices.Contains(targets, target.NameJira) || slices.Contains(targets, target.NameTempo)
+}
+
+// newPushTarget creates a target by name: jira, tempo or an HTTP target from targets config.
+// Jira target checks existing worklogs of the current user, if there are worklogs to create.
+func newPushTarget(name string, cred *model.Credentials, restClient rest.Client, jiraReq model.JiraRequest, csvRecords []csv.Record) (target.Target, error) {
+	switch name {
+	case target.NameJira:
+		jiraClient := newJiraClient(cred, restClient)
+		finder, err := newWorklogFinder(jiraClient, jiraReq, csvRecords)
 		if err != nil {
-			log.Println("Error unmarshalling json:", err)
+			return nil, err
 		}
-		jiraRes.IsSuccess = true
+		return &target.Jira{Client: jiraClient, Finder: finder}, nil
+	case target.NameTempo:
+		return newTempoTarget(newJiraClient(cred, restClient), restClient)
+	default:
+		return newHTTPTarget(name, restClient)
 	}
+}
 
-	log.Printf("Jira server responded: %v\n{%q}\n", res.Status, body)
-	respChn <- jiraRes
-	wg.Done()
+// newHTTPTarget creates HTTP target from targets.<name> config
+func newHTTPTarget(name string, restClient rest.Client) (*target.HTTP, error) {
+	key := "targets." + name
+	if !viper.IsSet(key) {
+		return nil, fmt.Errorf("unknown push target %q, expected %v, %v or a target defined in targets config", name, target.NameJira, target.NameTempo)
+	}
+	var create, update, remove target.Request
+	for k, r := range map[string]*target.Request{key: &create, key + ".update": &update, key + ".delete": &remove} {
+		if err := viper.UnmarshalKey(k, r); err != nil {
+			return nil, fmt.Errorf("%v: %w", k, err)
+		}
+	}
+	t, err := target.NewHTTP(name, create, update, remove, restClient)
+	if err != nil {
+		return nil, err
+	}
+	t.Retry = retryPolicy()
+	return t, nil
 }
 
-func updatePushedRecordsIds(resp []model.JiraResponse, csvRecords []csv.Record) {
-	if len(resp) == 0 {
-		return
+// newTempoTarget creates Tempo target from tempo config
+func newTempoTarget(jiraClient *jira.Client, restClient rest.Client) (*target.Tempo, error) {
+	client := tempo.NewClient(viper.GetString("tempo.url"), viper.GetString("tempo.token"), restClient)
+	client.Retry = retryPolicy()
+	t := &target.Tempo{
+		Client:            client,
+		Jira:              jiraClient,
+		AccountID:         viper.GetString("tempo.accountId"),
+		ProjectAttributes: map[string][]tempo.Attribute{},
 	}
-	log.Println("Generated JiraResponses:\n", resp)
-	log.Printf("CSV records before update: %q\n", csvRecords)
-	for _, responseItem := range resp {
-		if responseItem.IsSuccess {
-			csvRecords[responseItem.RowIdx].ID = responseItem.Id
-			log.Printf("Updated CSV record %q with ID %v\n", csvRecords[responseItem.RowIdx], responseItem.Id)
+	if err := viper.UnmarshalKey("tempo.attributes", &t.Attributes); err != nil {
+		return nil, fmt.Errorf("tempo.attributes: %w", err)
+	}
+	for project := range viper.GetStringMap("tempo.projects") {
+		var attrs []tempo.Attribute
+		if err := viper.UnmarshalKey("tempo.projects."+project+".attributes", &attrs); err != nil {
+			return nil, fmt.Errorf("tempo.projects.%v.attributes: %w", project, err)
 		}
+		t.ProjectAttributes[strings.ToUpper(project)] = attrs
 	}
+	return t, nil
 }
 
-func buildHTTPRequest(jiraTicket string, cred *model.Credentials, jr *model.JiraRequestRow) (*http.Request, error) {
-	jsonBody := []byte(jsonBodyStr(jr))
-	req, err := http.NewRequest("POST", buildPostURL(jiraTicket), bytes.NewBuffer(jsonBody))
-	req.Header.Add("Authorization", fmt.Sprintf("Basic %v", basicAuth(cred)))
-	req.Header.Add("Content-Type", "application/json")
-	log.Println("[Prepared HTTP Request]\n", req)
-	return req, err
+// newWorklogFinder creates a finder of the current user's worklogs if there are worklogs to create, nil otherwise.
+// Returns an error if the user is unknown, as worklogs created without the check might be duplicates.
+func newWorklogFinder(client *jira.Client, jiraReq model.JiraRequest, csvRecords []csv.Record) (*jira.WorklogFinder, error) {
+	if !slices.ContainsFunc(jiraReq, func(row model.JiraRequestRow) bool { return row.Method == http.MethodPost }) {
+		return nil, nil
+	}
+	me, err := client.Myself()
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve current user to check existing worklogs: %w", err)
+	}
+	var knownIDs []string
+	for _, rec := range csvRecords {
+		if rec.IsPushed() {
+			knownIDs = append(knownIDs, rec.ID)
+		}
+	}
+	return jira.NewWorklogFinder(client, me, knownIDs...), nil
 }
 
-func buildPostURL(jiraTicket string) string {
-	return strings.TrimSuffix(viper.GetString("Host"), "/") + fmt.Sprintf(jiraURLTemplate, jiraTicket)
+// sendSingleRequest creates, updates or deletes a worklog, depending on the row's method
+func sendSingleRequest(t target.Target, row model.JiraRequestRow) model.JiraResponse {
+	jiraRes := model.JiraResponse{
+		RowIdx:    row.GetIdx(),
+		Method:    row.Method,
+		Ticket:    row.Jiraticket,
+		Timespent: row.Timespent,
+		Comment:   row.Comment,
+		Started:   row.Started,
+		IsSuccess: false,
+	}
+	var worklog *target.Worklog
+	var resp *target.Response
+	var err error
+	switch row.Method {
+	case http.MethodPut:
+		worklog, resp, err = t.Update(row)
+	case http.MethodDelete:
+		resp, err = t.Delete(row)
+		worklog = &target.Worklog{ID: row.WorklogID}
+	default:
+		worklog, resp, err = t.Create(row)
+	}
+	jiraRes.Attempts = resp.Attempts
+	jiraRes.StatusCode = resp.StatusCode
+	if err != nil && row.Method == http.MethodDelete && resp.StatusCode == http.StatusNotFound {
+		// the worklog is deleted already, e.g. in the browser, so it's only forgotten
+		log.Printf("Worklog %v of %v is not found, it's deleted already\n", row.WorklogID, row.Jiraticket)
+		err = nil
+	}
+	if err != nil {
+		jiraRes.Err = err
+		var errs *jira.ErrorCollection
+		if errors.As(err, &errs) {
+			jiraRes.ErrorMessages = errs.ErrorMessages
+			jiraRes.Errors = errs.Errors
+		}
+		return jiraRes
+	}
+	jiraRes.Id = worklog.ID
+	jiraRes.IsSuccess = true
+	jiraRes.IsAdopted = worklog.IsAdopted
+	if row.Method == http.MethodDelete {
+		return jiraRes
+	}
+	jiraRes.IssueId = worklog.IssueID
+	jiraRes.Timespent = worklog.TimeSpent
+	jiraRes.Comment = worklog.Comment
+	jiraRes.Started = worklog.Started
+	return jiraRes
 }
 
-func jsonBodyStr(jr *model.JiraRequestRow) string {
-	jsonBodyTemplate := `{"timeSpent": "%v", "comment":"%v", "started": "%v"}`
-	return fmt.Sprintf(jsonBodyTemplate, jr.Timespent, jr.Comment, convertDateToDateTimeIso(jr.Started))
+// newJiraClient creates a client for the host and API version from config
+func newJiraClient(cred *model.Credentials, restClient rest.Client) *jira.Client {
+	client := jira.NewClient(viper.GetString("host"), viper.GetString("apiVersion"), cred, restClient)
+	client.Retry = retryPolicy()
+	return client
 }
 
-// convertDateToIDateTimeIso converts date "02 Jan 2006 15:04" to iso "2006-01-02T15:04:05.000-0700"
-func convertDateToDateTimeIso(date string) string {
-	parsedDate, err := time.ParseInLocation(config.DefaultDateTimePattern, date, time.Local)
-	if err != nil {
-		fmt.Printf("Error! %v", fmt.Errorf("couldn't parse date, %w", err))
-		log.Fatal(err)
+func pushConcurrency() int {
+	if n := viper.GetInt("push.concurrency"); n > 0 {
+		return n
 	}
-	const iso = "2006-01-02T15:04:05.000-0700"
-	return parsedDate.Format(iso)
+	return config.DefaultPushConcurrency
 }
 
-func basicAuth(cred *model.Credentials) string {
-	auth := cred.Username + ":" + cred.Password
-	return base64.StdEncoding.EncodeToString([]byte(auth))
+func retryPolicy() rest.RetryPolicy {
+	return rest.RetryPolicy{
+		MaxAttempts: viper.GetInt("push.retry.maxAttempts"),
+		BaseDelay:   viper.GetDuration("push.retry.baseDelay"),
+		MaxDelay:    viper.GetDuration("push.retry.maxDelay"),
+	}
 }
 
-func readCredentials() *model.Credentials {
-	//Read from config first
-	err := viper.UnmarshalKey("credentials", &creds)
-	if err != nil {
-		log.Fatalf("Unable to decode into struct, %v", err)
+func updatePushedRecordsIds(resp []model.JiraResponse, csvRecords []csv.Record) {
+	if len(resp) == 0 {
+		return
 	}
-	creds = *creds.Trim()
-	if creds.IsValid() {
-		return &creds
+	log.Println("Generated JiraResponses:\n", resp)
+	log.Printf("CSV records before update: %q\n", csvRecords)
+	for _, responseItem := range resp {
+		// only created worklogs get new IDs
+		if responseItem.IsSuccess && responseItem.Method == http.MethodPost {
+			csvRecords[responseItem.RowIdx].ID = responseItem.Id
+			log.Printf("Updated CSV record %q with ID %v\n", csvRecords[responseItem.RowIdx], responseItem.Id)
+		}
 	}
-	//Otherwise read from user input
-	reader := bufio.NewReader(os.Stdin)
-	fmt.Print("Enter Username: ")
-	creds.Username, _ = reader.ReadString('\n')
+}
 
-	fmt.Print("Enter Password: ")
-	bytePassword, err := term.ReadPassword(0)
+// resolveCredentials reads credentials from the provider, exits if they can't be resolved
+func resolveCredentials(provider credentials.Provider) *model.Credentials {
+	creds, err := provider.Credentials()
 	if err == nil {
-		fmt.Println("\nError reading password from user: ", err)
-		log.Println("\nError reading password from user: ", err)
+		_, err = creds.AuthorizationHeader()
+	}
+	if err != nil {
+		fmt.Println("Unable to resolve credentials:", err)
+		log.Fatalf("Unable to resolve credentials: %v", err)
 	}
-	creds.Password = string(bytePassword)
-	creds = *creds.Trim()
-	return &creds
+	return creds
 }
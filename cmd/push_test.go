@@ -16,15 +16,26 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"path"
 	"testing"
+	"time"
 
+	"github.com/philgal/jtl/internal/config"
+	"github.com/philgal/jtl/internal/credentials"
 	"github.com/philgal/jtl/internal/csv"
+	"github.com/philgal/jtl/internal/jira"
+	"github.com/philgal/jtl/internal/ledger"
 	"github.com/philgal/jtl/internal/model"
 	"github.com/philgal/jtl/internal/rest"
 	"github.com/philgal/jtl/internal/validation"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -50,6 +61,21 @@ func (c *MockRestClient) Do(req *http.Request) (*http.Response, error) {
 	}, nil
 }
 
+// MockFailingRestClient refuses worklogs to create
+type MockFailingRestClient struct{}
+
+func (c *MockFailingRestClient) Do(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodPost {
+		return (&MockRestClient{}).Do(req)
+	}
+	jsonb, _ := os.ReadFile("./cmd_testdata/jira_error_response.json")
+	return &http.Response{
+		StatusCode: 400,
+		Status:     "400 Bad Request",
+		Body:       io.NopCloser(bytes.NewReader(jsonb)),
+	}, nil
+}
+
 func init() {
 	restClient = &MockRestClient{}
 	validation.InitValidator()
@@ -64,24 +90,45 @@ func TestPost(t *testing.T) {
 	csvFile.ReadAll()
 
 	t.Run("Should unmarshall correct response", func(t *testing.T) {
-		jreq := model.NewJiraRequest(csvFile.Records)
-		jres := post(&model.Credentials{}, jreq, restClient)
+		jreq := model.NewJiraRequest(csvFile.Records, model.RecordFilter{})
+		jres := post(&model.Credentials{}, jreq, restClient, csvFile.Records, nil)
 
-		expected := []model.JiraResponse{{RowIdx: 1, Id: "100028", IssueId: "10002", Timespent: "3h 20m", Comment: "I did some work here.", Started: "2020-04-09T00:28:56.595+0000", IsSuccess: true}}
+		expected := []model.JiraResponse{{Target: "jira", RowIdx: 1, Method: "POST", Ticket: "TICKET-2", Id: "100028", IssueId: "10002", Timespent: "3h 20m", Comment: "I did some work here.", Started: "2020-04-09T00:28:56.595+0000", IsSuccess: true, Attempts: 1, StatusCode: 201}}
 
 		assert.Equal(t, 1, len(jres), "Bad response size")
 		assert.Exactly(t, expected, jres)
 	})
 }
 
+func TestPostFailure(t *testing.T) {
+
+	csvFile := csv.NewCsvFile("./cmd_testdata/not_empty.csv")
+	csvFile.ReadAll()
+
+	t.Run("Should collect status and Jira errors of failed request", func(t *testing.T) {
+		jreq := model.NewJiraRequest(csvFile.Records, model.RecordFilter{})
+		jres := post(&model.Credentials{}, jreq, &MockFailingRestClient{}, csvFile.Records, nil)
+
+		assert.Equal(t, 1, len(jres), "Bad response size")
+		assert.False(t, jres[0].IsSuccess)
+		assert.Equal(t, 400, jres[0].StatusCode)
+		assert.Equal(t, "TICKET-2", jres[0].Ticket)
+		assert.Equal(t, "15 Apr 2020 11:30", jres[0].Started)
+		assert.Equal(t, "Issue does not exist or you do not have permission to see it.; timeLogged: You must indicate the time spent working.", jres[0].FailureReason())
+
+		updatePushedRecordsIds(jres, csvFile.Records)
+		assert.Empty(t, csvFile.Records[1].ID)
+	})
+}
+
 func TestUpdateAfterPost(t *testing.T) {
 
 	csvFile := csv.NewCsvFile("./cmd_testdata/not_empty.csv")
 	csvFile.ReadAll()
 
 	t.Run("Should update row with id from response", func(t *testing.T) {
-		jreq := model.NewJiraRequest(csvFile.Records)
-		jres := post(&model.Credentials{}, jreq, restClient)
+		jreq := model.NewJiraRequest(csvFile.Records, model.RecordFilter{})
+		jres := post(&model.Credentials{}, jreq, restClient, csvFile.Records, nil)
 
 		assert.Equal(t, "1", csvFile.Records[0].ID)
 		assert.Empty(t, csvFile.Records[1].ID)
@@ -92,3 +139,498 @@ func TestUpdateAfterPost(t *testing.T) {
 		assert.Equal(t, "100028", csvFile.Records[1].ID)
 	})
 }
+
+func TestPushWithStaticCredentials(t *testing.T) {
+	dataFile, _ := setupPushFixture(t, map[string]string{})
+
+	t.Run("Should push without prompting and save pushed ids", func(t *testing.T) {
+		provider := credentials.Static{Creds: model.Credentials{Type: model.AuthBearer, Token: "pat-token"}}
+
+		jres, err := push(pushCmd, restClient, provider)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(jres))
+		assert.True(t, jres[0].IsSuccess)
+		csvFile := csv.NewCsvFile(dataFile)
+		csvFile.ReadAll()
+		assert.Equal(t, "100028", csvFile.Records[1].ID)
+	})
+}
+
+func TestPostToFakeJiraServer(t *testing.T) {
+	var gotPath string
+	var gotBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&gotBody)
+		jsonb, _ := os.ReadFile("./cmd_testdata/jira_response.json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write(jsonb)
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(viper.Reset)
+	viper.Set("host", server.URL)
+
+	jreq := model.JiraRequest{{Jiraticket: "TICKET-2", Timespent: "1h", Comment: "Line \"1\"\n- item", Started: "15 Apr 2020 11:30"}}
+
+	t.Run("Should post plain comment to API v2", func(t *testing.T) {
+		viper.Set("apiVersion", "2")
+		jreq := model.JiraRequest{{Jiraticket: "TICKET-2", Timespent: "1h", Comment: "Plain comment", Started: "15 Apr 2020 11:30"}}
+
+		jres := post(&model.Credentials{Username: "u", Password: "p"}, jreq, server.Client(), nil, nil)
+
+		assert.True(t, jres[0].IsSuccess)
+		assert.Equal(t, "/rest/api/2/issue/TICKET-2/worklog", gotPath)
+		assert.Equal(t, "Plain comment", gotBody["comment"])
+		assert.Equal(t, float64(3600), gotBody["timeSpentSeconds"])
+		assert.NotContains(t, gotBody, "visibility")
+	})
+
+	t.Run("Should post visibility restriction", func(t *testing.T) {
+		viper.Set("apiVersion", "2")
+		jreq := model.JiraRequest{{Jiraticket: "TICKET-2", Timespent: "1h", Started: "15 Apr 2020 11:30", Visibility: "role:Developers"}}
+
+		jres := post(&model.Credentials{Username: "u", Password: "p"}, jreq, server.Client(), nil, nil)
+
+		assert.True(t, jres[0].IsSuccess)
+		assert.Equal(t, map[string]any{"type": "role", "value": "Developers"}, gotBody["visibility"])
+	})
+
+	t.Run("Should post ADF comment to API v3", func(t *testing.T) {
+		viper.Set("apiVersion", "3")
+
+		jres := post(&model.Credentials{Username: "u", Password: "p"}, jreq, server.Client(), nil, nil)
+
+		assert.True(t, jres[0].IsSuccess)
+		assert.Equal(t, "/rest/api/3/issue/TICKET-2/worklog", gotPath)
+		comment, _ := json.Marshal(gotBody["comment"])
+		assert.JSONEq(t, `{"type":"doc","version":1,"content":[`+
+			`{"type":"paragraph","content":[{"type":"text","text":"Line \"1\""}]},`+
+			`{"type":"bulletList","content":[{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"item"}]}]}]}]}`,
+			string(comment))
+	})
+}
+
+func TestPushSyncsModifiedAndRemovedRecords(t *testing.T) {
+	jiraResponse, _ := os.ReadFile("./cmd_testdata/jira_response.json")
+	dataFile, server := setupPushFixture(t, map[string]string{
+		"GET /rest/api/2/myself":                      `{"name":"me","key":"me"}`,
+		"GET /rest/api/2/issue/TICKET-2/worklog":      `{"startAt":0,"total":0,"worklogs":[]}`,
+		"POST /rest/api/2/issue/TICKET-2/worklog":     string(jiraResponse),
+		"PUT /rest/api/2/issue/TICKET-1/worklog/1":    `{"id":"1","comment":"Row with ID, edited","timeSpent":"10m"}`,
+		"DELETE /rest/api/2/issue/TICKET-9/worklog/9": ``,
+	})
+
+	csvFile := csv.NewCsvFile(dataFile)
+	csvFile.ReadAll()
+	pushLedger, _ := ledger.Load(ledger.PathFor(dataFile))
+	pushLedger.Track(csvFile.Records[0])
+	pushLedger.Track(csv.Record{ID: "9", StartedTs: "13 Apr 2020 10:00", TimeSpent: "1h", Ticket: "TICKET-9"})
+	pushLedger.Save()
+	csvFile.Records[0].Comment = "Row with ID, edited"
+	csvFile.Write()
+
+	t.Run("Should create new, update modified and delete removed worklogs", func(t *testing.T) {
+		pushCmd.Flags().Set("delete-removed", "true")
+		t.Cleanup(func() { pushCmd.Flags().Set("delete-removed", "false") })
+
+		jres, err := push(pushCmd, server.Client(), credentials.Static{Creds: model.Credentials{Username: "u", Password: "p"}})
+
+		assert.NoError(t, err)
+		methods := map[string]bool{}
+		for _, r := range jres {
+			assert.True(t, r.IsSuccess, "%v %v failed: %v", r.Method, r.Ticket, r.FailureReason())
+			methods[r.Method] = true
+		}
+		assert.Equal(t, map[string]bool{"POST": true, "PUT": true, "DELETE": true}, methods)
+
+		synced, _ := ledger.Load(ledger.PathFor(dataFile))
+		assert.Len(t, synced.Entries, 2)
+		assert.NotContains(t, synced.Entries, "9")
+		csvFile.ReadAll()
+		assert.False(t, synced.IsModified(csvFile.Records[0]))
+	})
+}
+
+func TestPushKeepsWorklogsOfRemovedRecords(t *testing.T) {
+	dataFile, server := setupPushFixture(t, map[string]string{
+		"DELETE /rest/api/2/issue/TICKET-9/worklog/9": ``,
+	})
+	// the record of the worklog is removed, nothing else to push
+	os.WriteFile(dataFile, []byte("id,started,timespent,ticket,comment\n"), 0644)
+	pushLedger, _ := ledger.Load(ledger.PathFor(dataFile))
+	pushLedger.Track(csv.Record{ID: "9", StartedTs: "13 Apr 2020 10:00", TimeSpent: "1h", Ticket: "TICKET-9"})
+	pushLedger.Save()
+
+	t.Run("Should not delete worklogs of removed records without confirmation", func(t *testing.T) {
+		jres, err := push(pushCmd, server.Client(), credentials.Static{Creds: model.Credentials{Username: "u", Password: "p"}})
+
+		assert.NoError(t, err)
+		assert.Empty(t, jres)
+		kept, _ := ledger.Load(ledger.PathFor(dataFile))
+		assert.Contains(t, kept.Entries, "9")
+	})
+}
+
+func TestPushForgetsWorklogsDeletedRemotely(t *testing.T) {
+	// DELETE is not served: the worklog is deleted in Jira already
+	dataFile, server := setupPushFixture(t, map[string]string{})
+	os.WriteFile(dataFile, []byte("id,started,timespent,ticket,comment\n"), 0644)
+	pushLedger, _ := ledger.Load(ledger.PathFor(dataFile))
+	pushLedger.Track(csv.Record{ID: "9", StartedTs: "13 Apr 2020 10:00", TimeSpent: "1h", Ticket: "TICKET-9"})
+	pushLedger.Save()
+	pushCmd.Flags().Set("delete-removed", "true")
+	t.Cleanup(func() { pushCmd.Flags().Set("delete-removed", "false") })
+
+	t.Run("Should treat not found worklog as deleted and forget it", func(t *testing.T) {
+		jres, err := push(pushCmd, server.Client(), credentials.Static{Creds: model.Credentials{Username: "u", Password: "p"}})
+
+		assert.NoError(t, err)
+		assert.Len(t, jres, 1)
+		assert.True(t, jres[0].IsSuccess)
+		assert.Equal(t, 404, jres[0].StatusCode)
+		synced, _ := ledger.Load(ledger.PathFor(dataFile))
+		assert.Empty(t, synced.Entries)
+	})
+}
+
+func TestConfirmDeletions(t *testing.T) {
+	jreq := model.JiraRequest{
+		{Method: "POST", Jiraticket: "TICKET-1"},
+		{Method: "DELETE", Jiraticket: "TICKET-9", WorklogID: "9"},
+	}
+
+	t.Run("Should keep deletions confirmed by the user", func(t *testing.T) {
+		var asked model.JiraRequest
+		confirmed, filter := confirmDeletions(jreq, model.RecordFilter{}, func(deletions model.JiraRequest) bool {
+			asked = deletions
+			return true
+		})
+
+		assert.Equal(t, jreq, confirmed)
+		assert.Equal(t, model.JiraRequest{jreq[1]}, asked)
+		assert.False(t, filter.SkipRemoved)
+	})
+
+	t.Run("Should drop deletions refused by the user", func(t *testing.T) {
+		confirmed, filter := confirmDeletions(jreq, model.RecordFilter{}, func(model.JiraRequest) bool { return false })
+
+		assert.Equal(t, model.JiraRequest{jreq[0]}, confirmed)
+		assert.True(t, filter.SkipRemoved)
+	})
+
+	t.Run("Should not ask if there is nothing to delete", func(t *testing.T) {
+		confirmed, _ := confirmDeletions(jreq[:1], model.RecordFilter{}, func(model.JiraRequest) bool {
+			t.Fatal("asked to confirm")
+			return false
+		})
+
+		assert.Equal(t, jreq[:1], confirmed)
+	})
+}
+
+func TestPushAdoptsExistingWorklogs(t *testing.T) {
+	dataFile, server := setupPushFixture(t, map[string]string{
+		"GET /rest/api/2/myself": `{"name":"me","key":"me"}`,
+		"GET /rest/api/2/issue/TICKET-2/worklog": `{"startAt":0,"total":1,"worklogs":[
+			{"id":"500","author":{"name":"me","key":"me"},"started":"` + localJiraTime("15 Apr 2020 11:30") + `","timeSpentSeconds":600}
+		]}`,
+		// POST is not served: creating a worklog would fail
+	})
+
+	t.Run("Should adopt ID of already created worklog instead of posting", func(t *testing.T) {
+		jres, err := push(pushCmd, server.Client(), credentials.Static{Creds: model.Credentials{Username: "u", Password: "p"}})
+
+		assert.NoError(t, err)
+		assert.Len(t, jres, 1)
+		assert.True(t, jres[0].IsSuccess)
+		assert.True(t, jres[0].IsAdopted)
+		csvFile := csv.NewCsvFile(dataFile)
+		csvFile.ReadAll()
+		assert.Equal(t, "500", csvFile.Records[1].ID)
+	})
+}
+
+func TestPushDoesNotCreateUncheckedWorklogs(t *testing.T) {
+	var gotRequests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotRequests = append(gotRequests, r.Method+" "+r.URL.Path)
+		switch r.Method + " " + r.URL.Path {
+		case "GET /rest/api/2/myself":
+			io.WriteString(w, `{"name":"me","key":"me"}`)
+		case "GET /rest/api/2/issue/TICKET-2/worklog":
+			w.WriteHeader(http.StatusInternalServerError)
+			io.WriteString(w, `{"errorMessages":["Internal server error"]}`)
+		default:
+			jsonb, _ := os.ReadFile("./cmd_testdata/jira_response.json")
+			w.WriteHeader(http.StatusCreated)
+			w.Write(jsonb)
+		}
+	}))
+	t.Cleanup(server.Close)
+	dataFile := setupDataFile(t)
+	viper.Set("host", server.URL)
+
+	t.Run("Should fail the record instead of posting if existing worklogs can't be checked", func(t *testing.T) {
+		jres, err := push(pushCmd, server.Client(), credentials.Static{Creds: model.Credentials{Username: "u", Password: "p"}})
+
+		assert.NoError(t, err)
+		assert.Len(t, jres, 1)
+		assert.False(t, jres[0].IsSuccess)
+		assert.ErrorContains(t, jres[0].Err, "cannot check existing worklogs of TICKET-2")
+		assert.NotContains(t, gotRequests, "POST /rest/api/2/issue/TICKET-2/worklog")
+		csvFile := csv.NewCsvFile(dataFile)
+		csvFile.ReadAll()
+		assert.Empty(t, csvFile.Records[1].ID)
+	})
+}
+
+// setupPushFixture uses a copy of not_empty.csv as the data file and serves the responses by a fake Jira server.
+// It returns the path of the data file and the server; viper is reset when the test ends.
+func setupPushFixture(t *testing.T, responses map[string]string) (string, *httptest.Server) {
+	server := newFakeJiraServer(t, responses)
+	return setupDataFile(t), server
+}
+
+// setupDataFile uses a copy of not_empty.csv as the data file, for tests with own servers
+func setupDataFile(t *testing.T) string {
+	dataFile := path.Join(t.TempDir(), "data.csv")
+	data, err := os.ReadFile("./cmd_testdata/not_empty.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dataFile, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(viper.Reset)
+	viper.Set("data", dataFile)
+	config.InitDataFile()
+	return dataFile
+}
+
+// localJiraTime converts data file timestamp into Jira timestamp in local time zone
+func localJiraTime(ts string) string {
+	started, _ := time.ParseInLocation(config.DefaultDateTimePattern, ts, time.Local)
+	return jira.FormatTime(started)
+}
+
+func TestPushToFakeTempoServer(t *testing.T) {
+	var gotRequests []string
+	var gotBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotRequests = append(gotRequests, r.Method+" "+r.URL.Path)
+		switch r.Method + " " + r.URL.Path {
+		case "GET /rest/api/2/myself":
+			io.WriteString(w, `{"accountId":"acc-1"}`)
+		case "GET /rest/api/2/issue/SUP-2":
+			io.WriteString(w, `{"id":"10002","key":"SUP-2"}`)
+		case "POST /tempo/4/worklogs":
+			gotBody = nil
+			json.NewDecoder(r.Body).Decode(&gotBody)
+			io.WriteString(w, `{"tempoWorklogId":42,"issue":{"id":10002},"timeSpentSeconds":5400,"startDate":"2020-04-15","startTime":"11:30:00","description":"Support"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(viper.Reset)
+	viper.Set("host", server.URL)
+	viper.Set("push.target", "tempo")
+	viper.Set("tempo", map[string]any{
+		"url":   server.URL + "/tempo/4",
+		"token": "tempo-token",
+		"attributes": []map[string]any{
+			{"key": "_Account_", "value": "DEFAULT"},
+			{"key": "_WorkType_", "value": "Development"},
+		},
+		"projects": map[string]any{
+			"SUP": map[string]any{"attributes": []map[string]any{{"key": "_Account_", "value": "SUPPORT"}}},
+		},
+	})
+	jreq := model.JiraRequest{{Method: http.MethodPost, Jiraticket: "SUP-2", Timespent: "1h 30m", Comment: "Support", Started: "15 Apr 2020 11:30"}}
+
+	t.Run("Should create Tempo worklog with project attributes", func(t *testing.T) {
+		jres := post(&model.Credentials{Username: "u", Password: "p"}, jreq, server.Client(), nil, nil)
+
+		assert.True(t, jres[0].IsSuccess, jres[0].FailureReason())
+		assert.Equal(t, "42", jres[0].Id)
+		assert.Equal(t, "1h 30m", jres[0].Timespent)
+		assert.Contains(t, gotRequests, "POST /tempo/4/worklogs")
+		assert.Equal(t, float64(10002), gotBody["issueId"])
+		assert.Equal(t, "acc-1", gotBody["authorAccountId"])
+		assert.Equal(t, float64(5400), gotBody["timeSpentSeconds"])
+		assert.Equal(t, "2020-04-15", gotBody["startDate"])
+		assert.Equal(t, "11:30:00", gotBody["startTime"])
+		assert.Equal(t, []any{
+			map[string]any{"key": "_Account_", "value": "SUPPORT"},
+			map[string]any{"key": "_WorkType_", "value": "Development"},
+		}, gotBody["attributes"])
+	})
+}
+
+func TestPushMirrorsToHTTPTarget(t *testing.T) {
+	jiraResponse, _ := os.ReadFile("./cmd_testdata/jira_response.json")
+	var billingRequests []string
+	var billingBodies []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch request := r.Method + " " + r.URL.Path; request {
+		case "POST /rest/api/2/issue/TICKET-2/worklog":
+			w.WriteHeader(http.StatusCreated)
+			w.Write(jiraResponse)
+		case "GET /rest/api/2/myself", "GET /rest/api/2/issue/TICKET-2/worklog":
+			io.WriteString(w, `{}`)
+		default:
+			billingRequests = append(billingRequests, request+" "+r.Header.Get("X-Api-Key"))
+			var body map[string]any
+			json.NewDecoder(r.Body).Decode(&body)
+			billingBodies = append(billingBodies, body)
+			fmt.Fprintf(w, `{"data":{"id":"B-%v"}}`, len(billingRequests))
+		}
+	}))
+	t.Cleanup(server.Close)
+	dataFile := setupDataFile(t)
+	viper.Set("host", server.URL)
+	viper.Set("push.target", []string{"jira", "billing"})
+	viper.Set("targets", map[string]any{
+		"billing": map[string]any{
+			"url":     server.URL + "/billing/entries",
+			"headers": map[string]any{"x-api-key": "secret"},
+			"body":    `{"ticket":{{json .Ticket}},"minutes":{{.Minutes}},"date":{{json (.Started.Format "2006-01-02")}}}`,
+			"id":      "$.data.id",
+			"update":  map[string]any{"url": server.URL + "/billing/entries/{{.ID}}"},
+		},
+	})
+	creds := credentials.Static{Creds: model.Credentials{Username: "u", Password: "p"}}
+
+	t.Run("Should mirror records pushed to Jira", func(t *testing.T) {
+		jres, err := push(pushCmd, server.Client(), creds)
+
+		assert.NoError(t, err)
+		assert.Len(t, jres, 3)
+		for _, r := range jres {
+			assert.True(t, r.IsSuccess, "%v %v to %v failed: %v", r.Method, r.Ticket, r.Target, r.FailureReason())
+		}
+		assert.Equal(t, []string{"POST /billing/entries secret", "POST /billing/entries secret"}, billingRequests)
+		assert.Equal(t, map[string]any{"ticket": "TICKET-1", "minutes": float64(10), "date": "2020-04-14"}, billingBodies[0])
+		mirrorLedger, _ := ledger.Load(ledger.PathForTarget(dataFile, "billing"))
+		assert.Equal(t, "B-1", mirrorLedger.Entries["1"].ID)
+		assert.Equal(t, "B-2", mirrorLedger.Entries["100028"].ID)
+	})
+
+	t.Run("Should update mirrored record, not pushed to Jira again", func(t *testing.T) {
+		csvFile := csv.NewCsvFile(dataFile)
+		csvFile.ReadAll()
+		csvFile.Records[1].TimeSpent = "2h"
+		csvFile.Write()
+		// as if the change is already in Jira
+		pushLedger, _ := ledger.Load(ledger.PathFor(dataFile))
+		pushLedger.Track(csvFile.Records[1])
+		pushLedger.Save()
+
+		jres, err := push(pushCmd, server.Client(), creds)
+
+		assert.NoError(t, err)
+		assert.Len(t, jres, 1)
+		assert.Equal(t, "billing", jres[0].Target)
+		assert.Equal(t, "PUT /billing/entries/B-2 secret", billingRequests[len(billingRequests)-1])
+		assert.Equal(t, float64(120), billingBodies[len(billingBodies)-1]["minutes"])
+	})
+}
+
+func TestValidateTickets(t *testing.T) {
+	server := newFakeJiraServer(t, map[string]string{
+		"GET /rest/api/2/issue/OPEN-1":   `{"key":"OPEN-1","fields":{"summary":"Open issue","status":{"name":"Open"}}}`,
+		"GET /rest/api/2/issue/CLOSED-1": `{"key":"CLOSED-1","fields":{"summary":"Closed issue","status":{"name":"Closed"}}}`,
+		"GET /rest/api/2/mypermissions":  `{"permissions":{"WORK_ON_ISSUES":{"havePermission":true}}}`,
+	})
+	viper.Set("push.closedStatuses", []string{"Closed"})
+	t.Cleanup(viper.Reset)
+	client := jira.NewClient(server.URL, "2", &model.Credentials{Username: "u", Password: "p"}, server.Client())
+	jreq := model.JiraRequest{
+		{Method: http.MethodPost, Jiraticket: "OPEN-1", Timespent: "1h", Started: "15 Apr 2020 11:30"},
+		{Method: http.MethodPost, Jiraticket: "TYPO-1", Timespent: "1h", Started: "15 Apr 2020 12:30"},
+		{Method: http.MethodPut, WorklogID: "7", Jiraticket: "CLOSED-1", Timespent: "1h", Started: "15 Apr 2020 13:30"},
+		{Method: http.MethodDelete, WorklogID: "9", Jiraticket: "TYPO-1"},
+	}
+
+	t.Run("Should skip requests to invalid tickets and confirm the rest", func(t *testing.T) {
+		confirmed := 0
+		valid, skipped, proceed := validateTickets(client, jreq, func(n int) bool { confirmed = n; return true })
+
+		assert.True(t, proceed)
+		assert.Equal(t, 2, confirmed)
+		assert.Equal(t, []string{"OPEN-1", "TYPO-1"}, []string{valid[0].Jiraticket, valid[1].Jiraticket})
+		assert.Equal(t, http.MethodDelete, valid[1].Method)
+		assert.Len(t, skipped, 2)
+		assert.Equal(t, "skipped: issue does not exist or is not visible", skipped[0].FailureReason())
+		assert.Equal(t, "skipped: issue is Closed", skipped[1].FailureReason())
+	})
+
+	t.Run("Should not proceed if not confirmed", func(t *testing.T) {
+		_, _, proceed := validateTickets(client, jreq, func(int) bool { return false })
+
+		assert.False(t, proceed)
+	})
+}
+
+func TestPushCancelledWithoutConfirmation(t *testing.T) {
+	dataFile, server := setupPushFixture(t, map[string]string{
+		"GET /rest/api/2/issue/TICKET-2": `{"key":"TICKET-2","fields":{"summary":"Open issue","status":{"name":"Open"}}}`,
+		"GET /rest/api/2/mypermissions":  `{"permissions":{"WORK_ON_ISSUES":{"havePermission":true}}}`,
+	})
+	viper.Set("push.validate", true)
+
+	t.Run("Should return error if push can't be confirmed without a terminal", func(t *testing.T) {
+		jres, err := push(pushCmd, server.Client(), credentials.Static{Creds: model.Credentials{Username: "u", Password: "p"}})
+
+		assert.ErrorIs(t, err, errPushCancelled)
+		assert.Empty(t, jres)
+		csvFile := csv.NewCsvFile(dataFile)
+		csvFile.ReadAll()
+		assert.Empty(t, csvFile.Records[1].ID)
+	})
+}
+
+func TestSelectRequests(t *testing.T) {
+	jreq := model.JiraRequest{
+		{Method: http.MethodPost, Jiraticket: "SUP-1", Timespent: "1h", Started: "15 Apr 2020 11:30", Comment: "first"},
+		{Method: http.MethodPost, Jiraticket: "SUP-2", Timespent: "2h", Started: "15 Apr 2020 12:30", Comment: "second"},
+	}
+
+	t.Run("Should push only ticked requests", func(t *testing.T) {
+		var offered []string
+		selected, err := selectRequests(jreq, func(lines []string) ([]int, error) {
+			offered = lines
+			return []int{1}, nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.JiraRequest{jreq[1]}, selected)
+		assert.Equal(t, "POST   15 Apr 2020 11:30 SUP-1        1h       first", offered[0])
+	})
+}
+
+func TestPushFilter(t *testing.T) {
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		addPushFilterFlags(cmd.Flags())
+		cmd.Flags().Parse(args)
+		return cmd
+	}
+
+	t.Run("Should read filters from flags", func(t *testing.T) {
+		filter, err := pushFilter(newCmd("--date", "15 Apr 2020", "--project", "SUP,DEV", "--before", "today"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2020, 4, 15, 0, 0, 0, 0, time.Local), filter.From)
+		assert.Equal(t, filter.From, filter.To)
+		assert.Equal(t, []string{"SUP", "DEV"}, filter.Projects)
+		assert.False(t, filter.Before.IsZero())
+	})
+
+	t.Run("Should reject date with range", func(t *testing.T) {
+		_, err := pushFilter(newCmd("--date", "today", "--from", "yesterday"))
+
+		assert.ErrorContains(t, err, "--date can't be combined")
+	})
+}
@@ -0,0 +1,290 @@
+// Copyright © 2020 Philipp Galichkin
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/philgal/jtl/internal/config"
+	"github.com/philgal/jtl/internal/csv"
+	"github.com/philgal/jtl/internal/dates"
+	"github.com/philgal/jtl/internal/jira"
+	"github.com/philgal/jtl/internal/ledger"
+	"github.com/philgal/jtl/internal/model"
+	"github.com/philgal/jtl/internal/validation"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const recordsHelp = `
+Records of the current data file are addressed by a selector:
+
+  <n>           number of the record, as shown by 'jtl report --all'
+  last          the last record in the data file
+  id:<id>       the record pushed as the worklog with the ID
+  <ticket>      the last record of the ticket or alias
+
+Edited records are validated like logged ones. Pushed records are synced by the next push: an edited one updates its worklog,
+a removed one deletes it, see 'jtl push --help'. Removing a pushed record, changing its ticket or changing a pushed record
+unknown to the ledger of pushed records are refused without --force.
+
+Examples:
+  jtl edit 5 -t 2h -m "Code review"
+  jtl edit JIRA-101 -d "yesterday 10:00"
+  jtl amend -m "Daily standup"
+  jtl rm 5 7
+`
+
+// editCmd represents the edit command
+var editCmd = &cobra.Command{
+	Use:   "edit <n|selector>",
+	Short: "Changes a record in the data file",
+	Long:  recordsHelp,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		editRecords(cmd, args[0])
+	},
+}
+
+// amendCmd represents the amend command
+var amendCmd = &cobra.Command{
+	Use:   "amend",
+	Short: "Changes the last record in the data file",
+	Long:  recordsHelp,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		editRecords(cmd, "last")
+	},
+}
+
+// rmCmd represents the rm command
+var rmCmd = &cobra.Command{
+	Use:   "rm <n|selector>...",
+	Short: "Removes records from the data file",
+	Long:  recordsHelp,
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		force, _ := cmd.Flags().GetBool("force")
+		csvFile := csv.NewCsvFile(config.DataFilePath())
+		csvFile.ReadAll()
+		pushLedger := loadPushLedger(csvFile.Path)
+		var indexes []int
+		for _, selector := range args {
+			idx, err := selectRecord(csvFile.Records, selector)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			if err := checkPushedRemoval(pushLedger, csvFile.Records[idx], force); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			if !slices.Contains(indexes, idx) {
+				indexes = append(indexes, idx)
+			}
+		}
+		// remove from the end, so that indexes of the rest don't shift
+		slices.Sort(indexes)
+		for _, idx := range slices.Backward(indexes) {
+			rec := csvFile.Records[idx]
+			csvFile.RemoveRecord(idx)
+			fmt.Printf("Removed #%v: %v %v %v %q\n", idx+1, rec.StartedTs, rec.Ticket, rec.TimeSpent, rec.Comment)
+			if rec.IsPushed() && pushLedger.IsTracked(rec) {
+				fmt.Printf("Worklog %v of %v will be deleted in Jira by the next push, once confirmed\n", rec.ID, rec.Ticket)
+			} else if rec.IsPushed() {
+				fmt.Printf("Worklog %v of %v stays in Jira\n", rec.ID, rec.Ticket)
+			}
+		}
+		csvFile.Write()
+		displayReport()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(editCmd, amendCmd, rmCmd)
+	for _, cmd := range []*cobra.Command{editCmd, amendCmd} {
+		addRecordFlags(cmd.Flags())
+	}
+	for _, cmd := range []*cobra.Command{editCmd, amendCmd, rmCmd} {
+		cmd.Flags().Bool("force", false, "Change pushed records, even if the next push can't sync the change to Jira")
+	}
+}
+
+// addRecordFlags adds flags of the record's fields. Only fields of the flags set are changed.
+func addRecordFlags(flags *pflag.FlagSet) {
+	flags.StringP(timeCmdStr, "t", "", "Time spent, e.g. 1h 30m")
+	flags.StringP(messageCmdStr, "m", "", "Comment to the work log")
+	flags.StringP(dateCmdStr, "d", "", "Date and time when the work has been started, e.g. \"mon 9:30\" or \"14 Apr 2020 10:00\"")
+	flags.String(ticketCmdStr, "", "Ticket or alias")
+	flags.String("visibility", "", "Restrict the worklog to a role or a group, e.g. role:Developers, empty to make it visible to all")
+}
+
+// editRecords changes the selected record of the data file by the flags set
+func editRecords(cmd *cobra.Command, selector string) {
+	force, _ := cmd.Flags().GetBool("force")
+	csvFile := csv.NewCsvFile(config.DataFilePath())
+	csvFile.ReadAll()
+	idx, err := selectRecord(csvFile.Records, selector)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	before := csvFile.Records[idx]
+	after, err := changeRecord(cmd.Flags(), before)
+	if err == nil {
+		err = validateRecord(after)
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if after == before {
+		fmt.Println("Nothing to change, set fields with flags, see 'jtl edit --help'")
+		return
+	}
+	pushLedger := loadPushLedger(csvFile.Path)
+	if err := checkPushedEdit(pushLedger, before, after, force); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	csvFile.UpdateRecord(after)
+	csvFile.Write()
+	fmt.Printf("Changed #%v: %v %v %v %q\n", idx+1, after.StartedTs, after.Ticket, after.TimeSpent, after.Comment)
+	if after.IsPushed() && pushLedger.IsTracked(after) && strings.EqualFold(before.Ticket, after.Ticket) {
+		fmt.Printf("Worklog %v of %v will be updated in Jira by the next push\n", after.ID, after.Ticket)
+	} else if after.IsPushed() {
+		fmt.Printf("Worklog %v of %v won't be updated in Jira\n", before.ID, before.Ticket)
+	}
+	touchIssues(after.Ticket)
+	displayReport()
+}
+
+// selectRecord returns the index of the record addressed by the selector, see recordsHelp
+func selectRecord(recs []csv.Record, selector string) (int, error) {
+	selector = strings.TrimSpace(selector)
+	if len(recs) == 0 {
+		return 0, errors.New("no records in the data file")
+	}
+	if n, err := strconv.Atoi(selector); err == nil {
+		if n < 1 || n > len(recs) {
+			return 0, fmt.Errorf("no record #%v, the data file has %v records", n, len(recs))
+		}
+		return n - 1, nil
+	}
+	if strings.EqualFold(selector, "last") {
+		return len(recs) - 1, nil
+	}
+	if id, found := strings.CutPrefix(selector, "id:"); found {
+		for i, r := range recs {
+			if r.IsPushed() && r.ID == strings.TrimSpace(id) {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("no record pushed as the worklog %v", id)
+	}
+	ticket := model.ResolveTicket(selector)
+	for i := len(recs) - 1; i >= 0; i-- {
+		if strings.EqualFold(recs[i].Ticket, ticket) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("no record of %v in the data file", ticket)
+}
+
+// changeRecord sets fields of the record by the flags set
+func changeRecord(flags *pflag.FlagSet, rec csv.Record) (csv.Record, error) {
+	if flags.Changed(timeCmdStr) {
+		rec.TimeSpent, _ = flags.GetString(timeCmdStr)
+	}
+	if flags.Changed(messageCmdStr) {
+		rec.Comment, _ = flags.GetString(messageCmdStr)
+	}
+	if flags.Changed(dateCmdStr) {
+		value, _ := flags.GetString(dateCmdStr)
+		started, err := dates.Parse(value)
+		if err != nil {
+			return rec, err
+		}
+		rec.StartedTs = started.Format(config.DefaultDateTimePattern)
+	}
+	if flags.Changed(ticketCmdStr) {
+		value, _ := flags.GetString(ticketCmdStr)
+		rec.Ticket = model.ResolveTicket(strings.TrimSpace(value))
+	}
+	if flags.Changed("visibility") {
+		value, _ := flags.GetString("visibility")
+		v, err := jira.ParseVisibility(value)
+		if err != nil {
+			return rec, err
+		}
+		rec.Visibility = v.String()
+	}
+	return rec, nil
+}
+
+// validateRecord checks the record by its validation tags and its started time
+func validateRecord(rec csv.Record) error {
+	if err := validation.Validate.Struct(rec); err != nil {
+		return fmt.Errorf("invalid record: %w", err)
+	}
+	if _, err := time.Parse(config.DefaultDateTimePattern, rec.StartedTs); err != nil {
+		return fmt.Errorf("invalid record: started %q, expected format %q", rec.StartedTs, config.DefaultDateTimePattern)
+	}
+	return nil
+}
+
+// checkPushedEdit refuses changes of a pushed record, which the next push can't sync to Jira, unless forced
+func checkPushedEdit(l *ledger.Ledger, before, after csv.Record, force bool) error {
+	if !before.IsPushed() || force {
+		return nil
+	}
+	if !strings.EqualFold(before.Ticket, after.Ticket) {
+		return fmt.Errorf("record #%v is pushed as worklog %v of %v, its ticket can't be changed in Jira; use --force, "+
+			"or clear its ID to push it as a new worklog and remove the old one in Jira", before.GetIdx()+1, before.ID, before.Ticket)
+	}
+	if !l.IsTracked(before) {
+		return fmt.Errorf("record #%v is pushed as worklog %v, but it's unknown to the ledger, so the change won't be pushed; use --force to change it anyway",
+			before.GetIdx()+1, before.ID)
+	}
+	return nil
+}
+
+// checkPushedRemoval refuses removal of a pushed record, unless forced, as it deletes its worklog in Jira
+func checkPushedRemoval(l *ledger.Ledger, rec csv.Record, force bool) error {
+	if !rec.IsPushed() || force {
+		return nil
+	}
+	if l.IsTracked(rec) {
+		return fmt.Errorf("record #%v is pushed as worklog %v of %v, the next push will delete it in Jira; use --force to remove it",
+			rec.GetIdx()+1, rec.ID, rec.Ticket)
+	}
+	return fmt.Errorf("record #%v is pushed as worklog %v of %v, but it's unknown to the ledger, so the worklog will stay in Jira; use --force to remove it anyway",
+		rec.GetIdx()+1, rec.ID, rec.Ticket)
+}
+
+func loadPushLedger(dataFile string) *ledger.Ledger {
+	l, err := ledger.Load(ledger.PathFor(dataFile))
+	if err != nil {
+		fmt.Println("Error reading ledger of pushed records:", err)
+		os.Exit(1)
+	}
+	return l
+}
@@ -0,0 +1,124 @@
+// Copyright © 2020 Philipp Galichkin
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"path"
+	"testing"
+
+	"github.com/philgal/jtl/internal/csv"
+	"github.com/philgal/jtl/internal/ledger"
+	"github.com/philgal/jtl/internal/validation"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func testRecords() []csv.Record {
+	f := csv.File{}
+	f.AddRecord(csv.Record{ID: "100", StartedTs: "14 Apr 2020 08:45", Comment: "Review", TimeSpent: "2h", Ticket: "JIRA-1"})
+	f.AddRecord(csv.Record{StartedTs: "14 Apr 2020 10:45", Comment: "Meeting", TimeSpent: "1h", Ticket: "MEET-7"})
+	f.AddRecord(csv.Record{StartedTs: "14 Apr 2020 11:45", Comment: "wip", TimeSpent: "4h", Ticket: "JIRA-1"})
+	return f.Records
+}
+
+func TestSelectRecord(t *testing.T) {
+	viper.Set("alias", map[string]any{"standup": "MEET-7"})
+	t.Cleanup(func() { viper.Set("alias", nil) })
+	recs := testRecords()
+	tests := []struct {
+		selector string
+		want     int
+	}{
+		{"1", 0},
+		{"3", 2},
+		{"last", 2},
+		{"id:100", 0},
+		{"jira-1", 2},
+		{"standup", 1},
+	}
+	for _, test := range tests {
+		t.Run("Should select a record by "+test.selector, func(t *testing.T) {
+			idx, err := selectRecord(recs, test.selector)
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, idx)
+		})
+	}
+	for _, selector := range []string{"0", "4", "id:200", "NONE-1"} {
+		t.Run("Should not select a record by "+selector, func(t *testing.T) {
+			_, err := selectRecord(recs, selector)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestChangeRecord(t *testing.T) {
+	validation.InitValidator()
+	newFlags := func(args ...string) *pflag.FlagSet {
+		flags := pflag.NewFlagSet("edit", pflag.ContinueOnError)
+		addRecordFlags(flags)
+		flags.Parse(args)
+		return flags
+	}
+	t.Run("Should change only fields of flags set", func(t *testing.T) {
+		rec, err := changeRecord(newFlags("-t", "30m", "--visibility", "role:Developers"), testRecords()[1])
+		assert.NoError(t, err)
+		assert.NoError(t, validateRecord(rec))
+		assert.Equal(t, "30m", rec.TimeSpent)
+		assert.Equal(t, "Meeting", rec.Comment)
+		assert.Equal(t, "role:Developers", rec.Visibility)
+	})
+	t.Run("Should not validate invalid time spent", func(t *testing.T) {
+		rec, err := changeRecord(newFlags("-t", "2 hours"), testRecords()[1])
+		assert.NoError(t, err)
+		assert.Error(t, validateRecord(rec))
+	})
+	t.Run("Should not change invalid started time", func(t *testing.T) {
+		_, err := changeRecord(newFlags("-d", "yesterday at noon"), testRecords()[1])
+		assert.Error(t, err)
+	})
+	t.Run("Should not change invalid visibility", func(t *testing.T) {
+		_, err := changeRecord(newFlags("--visibility", "everyone"), testRecords()[1])
+		assert.Error(t, err)
+	})
+}
+
+func TestCheckPushedRecords(t *testing.T) {
+	recs := testRecords()
+	l, _ := ledger.Load(path.Join(t.TempDir(), "ledger.json"))
+	edited := recs[0]
+	edited.TimeSpent = "3h"
+	moved := recs[0]
+	moved.Ticket = "JIRA-2"
+
+	t.Run("Should refuse changes of pushed records unknown to the ledger", func(t *testing.T) {
+		assert.Error(t, checkPushedEdit(l, recs[0], edited, false))
+		assert.Error(t, checkPushedRemoval(l, recs[0], false))
+		assert.NoError(t, checkPushedEdit(l, recs[0], edited, true))
+	})
+	l.Track(recs[0])
+	t.Run("Should allow edits of tracked pushed records", func(t *testing.T) {
+		assert.NoError(t, checkPushedEdit(l, recs[0], edited, false))
+	})
+	t.Run("Should refuse ticket changes and removal of pushed records, unless forced", func(t *testing.T) {
+		assert.Error(t, checkPushedEdit(l, recs[0], moved, false))
+		assert.Error(t, checkPushedRemoval(l, recs[0], false))
+		assert.NoError(t, checkPushedRemoval(l, recs[0], true))
+	})
+	t.Run("Should allow changes of not pushed records", func(t *testing.T) {
+		assert.NoError(t, checkPushedEdit(l, recs[2], recs[1], false))
+		assert.NoError(t, checkPushedRemoval(l, recs[2], false))
+	})
+}
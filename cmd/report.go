@@ -17,6 +17,7 @@ package cmd
 import (
 	"github.com/philgal/jtl/internal/config"
 	"github.com/philgal/jtl/internal/csv"
+	"github.com/philgal/jtl/internal/model"
 	"github.com/philgal/jtl/internal/report"
 
 	"github.com/spf13/cobra"
@@ -26,12 +27,23 @@ import (
 var reportCmd = &cobra.Command{
 	Use:   "report",
 	Short: "Displays summarized report for data file",
+	Long: `Displays daily and monthly summary of the data file, or all records with --all.
+Records can be filtered by tickets, or their aliases, and by projects.
+Numbers of records, shown with --all, address them in 'jtl edit' and 'jtl rm'.
+
+Examples:
+  jtl report --ticket jt1
+  jtl report -a --project SUP,DEV
+`,
 	Run: func(cmd *cobra.Command, args []string) {
+		tickets, _ := cmd.Flags().GetStringSlice("ticket")
+		projects, _ := cmd.Flags().GetStringSlice("project")
+		filter := model.RecordFilter{Tickets: model.ResolveTickets(tickets), Projects: projects}
 		displayAll, _ := cmd.Flags().GetBool("all")
 		if displayAll {
-			displayAllRecords()
+			displayAllRecords(filter)
 		} else {
-			displayReport()
+			displayFilteredReport(filter)
 		}
 	},
 }
@@ -39,21 +51,32 @@ var reportCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(reportCmd)
 	reportCmd.Flags().BoolP("all", "a", false, "Display all records from the current data file")
+	reportCmd.Flags().StringSlice("ticket", nil, "Display only records of the tickets or aliases, e.g. --ticket JIRA-1,jt1")
+	reportCmd.Flags().StringSlice("project", nil, "Display only records of the projects, e.g. --project SUP")
 }
 
-func displayAllRecords() {
+// readRecords reads records of the data file, selected by the filter
+func readRecords(filter model.RecordFilter) []csv.Record {
 	fcsv := csv.NewCsvFile(config.DataFilePath())
 	fcsv.ReadAll()
-	dailyRecords := report.NewDailyReport(fcsv.Records, true)
+	return fcsv.Filter(filter.Match)
+}
+
+func displayAllRecords(filter model.RecordFilter) {
+	dailyRecords := report.NewDailyReport(readRecords(filter), true).WithSummaries(issueSummaries())
 	dailyRecords.Print()
 }
 
 func displayReport() {
-	fcsv := csv.NewCsvFile(config.DataFilePath())
-	fcsv.ReadAll()
+	displayFilteredReport(model.RecordFilter{})
+}
+
+func displayFilteredReport(filter model.RecordFilter) {
+	records := readRecords(filter)
+	summaries := issueSummaries()
 	reports := []report.Printable{
-		report.NewDailyReport(fcsv.Records, false),
-		report.NewMonthlyReport(fcsv.Records),
+		report.NewDailyReport(records, false).WithSummaries(summaries),
+		report.NewMonthlyReport(records).WithSummaries(summaries),
 	}
 	for _, report := range reports {
 		report.Print()
@@ -0,0 +1,254 @@
+// Copyright © 2020 Philipp Galichkin
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/philgal/jtl/internal/config"
+	"github.com/philgal/jtl/internal/dates"
+	"github.com/philgal/jtl/internal/duration"
+	"github.com/philgal/jtl/internal/jira"
+	"github.com/philgal/jtl/internal/log"
+	"github.com/philgal/jtl/internal/model"
+	"github.com/philgal/jtl/internal/timer"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const timerHelp = `
+Timer tracks work on a ticket live, instead of logging time spent after the fact. The running timer is kept in ~/.jtl/timer.json.
+On stop, the time is rounded by <timer.rounding> policy: none, or nearest, up or down to a duration, e.g. up:15m.
+A timer running across midnight is logged as a record per day, the total time is rounded and then split between the days. Records are added with auto-fitting, if -f is set.
+
+  timer:
+    rounding: nearest:5m
+
+Examples:
+  jtl start JIRA-101 -m "Code review"
+  jtl status
+  jtl switch l666 -m "Meeting" -d 10:30
+  jtl stop
+`
+
+// startCmd represents the start command
+var startCmd = &cobra.Command{
+	Use:               "start <ticket>",
+	Short:             "Starts a timer of work on the ticket",
+	Long:              timerHelp,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeRecentIssues,
+	Run: func(cmd *cobra.Command, args []string) {
+		if running := loadTimer(); running != nil {
+			fmt.Printf("Timer of %v is already running since %v, stop it or switch to another ticket\n",
+				running.Ticket, running.Started.Format(config.DefaultDateTimePattern))
+			os.Exit(1)
+		}
+		t := newTimer(cmd, args[0], timeFlag(cmd, dateCmdStr))
+		saveTimer(t)
+		fmt.Printf("Started %v at %v\n", t.Ticket, t.Started.Format(config.DefaultDateTimePattern))
+	},
+}
+
+// stopCmd represents the stop command
+var stopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stops the timer and logs its time",
+	Long:  timerHelp,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		running := runningTimer()
+		if discard, _ := cmd.Flags().GetBool("discard"); discard {
+			clearTimer()
+			fmt.Printf("Timer of %v discarded\n", running.Ticket)
+			return
+		}
+		stopTimer(cmd, running, timeFlag(cmd, dateCmdStr))
+		displayReport()
+	},
+}
+
+// switchCmd represents the switch command
+var switchCmd = &cobra.Command{
+	Use:               "switch <ticket>",
+	Short:             "Stops the timer, logs its time and starts a timer of another ticket",
+	Long:              timerHelp,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeRecentIssues,
+	Run: func(cmd *cobra.Command, args []string) {
+		running := runningTimer()
+		now := timeFlag(cmd, dateCmdStr)
+		next := newTimer(cmd, args[0], now)
+		stopTimer(cmd, running, now)
+		saveTimer(next)
+		fmt.Printf("Started %v at %v\n", next.Ticket, next.Started.Format(config.DefaultDateTimePattern))
+		displayReport()
+	},
+}
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Shows the running timer",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		running := loadTimer()
+		if running == nil {
+			fmt.Println("No timer is running")
+			return
+		}
+		elapsed := running.Elapsed(time.Now())
+		fmt.Printf("%v %q, running since %v for %v\n", running.Ticket, running.Comment,
+			running.Started.Format(config.DefaultDateTimePattern), duration.ToString(int(elapsed/time.Minute)))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(startCmd, stopCmd, switchCmd, statusCmd)
+	for _, cmd := range []*cobra.Command{startCmd, switchCmd} {
+		cmd.Flags().StringP(messageCmdStr, "m", "", "Comment to the work log. Default - comment of the alias or \"wip\"")
+		cmd.Flags().String("visibility", "", "Restrict the worklog to a role or a group, e.g. role:Developers")
+	}
+	startCmd.Flags().StringP(dateCmdStr, "d", "", "Date and time when the work has been started, e.g. 9:30 or \"yesterday 17:00\". Default - now")
+	stopCmd.Flags().StringP(dateCmdStr, "d", "", "Date and time when the work has been stopped, e.g. 9:30 or \"yesterday 17:00\". Default - now")
+	switchCmd.Flags().StringP(dateCmdStr, "d", "", "Date and time when the work has been switched, e.g. 9:30 or \"yesterday 17:00\". Default - now")
+	for _, cmd := range []*cobra.Command{stopCmd, switchCmd} {
+		cmd.Flags().BoolP("auto-fitting", "f", false, "Add records with auto-fitting to the maximum daily duration")
+	}
+	stopCmd.Flags().Bool("discard", false, "Stop the timer without logging its time")
+}
+
+// newTimer creates a timer of the ticket or alias with comment and visibility from flags, the alias or config
+func newTimer(cmd *cobra.Command, ticketOrAlias string, started time.Time) *timer.Timer {
+	t := &timer.Timer{Ticket: ticketOrAlias, Started: started}
+	t.Comment, _ = cmd.Flags().GetString(messageCmdStr)
+	t.Visibility, _ = cmd.Flags().GetString("visibility")
+	if a, found := model.ResolveAlias(ticketOrAlias); found {
+		t.Ticket = a.Ticket
+		if t.Comment == "" {
+			t.Comment = a.Comment
+		}
+		if t.Visibility == "" {
+			t.Visibility = a.Visibility
+		}
+	}
+	model.ValidateJiraTicketFormat(t.Ticket)
+	if t.Comment == "" {
+		t.Comment = "wip"
+	}
+	if t.Visibility == "" {
+		t.Visibility = model.DefaultVisibility(t.Ticket)
+	}
+	v, err := jira.ParseVisibility(t.Visibility)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	t.Visibility = v.String()
+	touchIssues(t.Ticket)
+	return t
+}
+
+// stopTimer logs time of the timer, rounded by timer.rounding, as a record per day and clears it.
+// The total time is rounded, so a short timer across midnight is not rounded up per day.
+func stopTimer(cmd *cobra.Command, t *timer.Timer, stopped time.Time) {
+	if !stopped.After(t.Started) {
+		fmt.Printf("Stop time must be after the timer started at %v\n", t.Started.Format(config.DefaultDateTimePattern))
+		os.Exit(1)
+	}
+	rounding, err := timer.ParseRounding(viper.GetString("timer.rounding"))
+	if err != nil {
+		fmt.Println("timer.rounding:", err)
+		os.Exit(1)
+	}
+	autoFitting, _ := cmd.Flags().GetBool("auto-fitting")
+	spans := t.Split(stopped)
+	rounded := rounding.RoundSpans(spans)
+	for i, span := range spans {
+		if i > 0 {
+			// the timer restarts after the logged days, so if logging exits, the next stop doesn't log them again
+			t.Started = span.Started
+			saveTimer(t)
+		}
+		minutes := int(rounded[i] / time.Minute)
+		startedTs := span.Started.Format(config.DefaultDateTimePattern)
+		if minutes <= 0 {
+			fmt.Printf("Not logging %v of %v started at %v, rounded to 0m by %v\n", span.Duration.Round(time.Second), t.Ticket, startedTs, rounding)
+			continue
+		}
+		executorArgs := log.ExecutorArgs{
+			Ticket:     t.Ticket,
+			TimeSpent:  duration.ToString(minutes),
+			Comment:    t.Comment,
+			StartedTs:  startedTs,
+			Visibility: t.Visibility}
+		if autoFitting {
+			log.AutoFitting{ExecutorArgs: executorArgs}.Execute()
+		} else {
+			log.Normal{ExecutorArgs: executorArgs}.Execute()
+		}
+		fmt.Printf("Logged %v of %v started at %v\n", executorArgs.TimeSpent, t.Ticket, startedTs)
+	}
+	clearTimer()
+}
+
+// timeFlag parses the date expression of the flag, now if it's not set
+func timeFlag(cmd *cobra.Command, name string) time.Time {
+	value, _ := cmd.Flags().GetString(name)
+	if value == "" {
+		return time.Now()
+	}
+	t, err := dates.Parse(value)
+	if err != nil {
+		fmt.Printf("Invalid --%v: %v\n", name, err)
+		os.Exit(1)
+	}
+	return t
+}
+
+func loadTimer() *timer.Timer {
+	t, err := timer.Load(timer.DefaultPath())
+	if err != nil {
+		fmt.Println("Error reading timer:", err)
+		os.Exit(1)
+	}
+	return t
+}
+
+// runningTimer returns the running timer, exits if there is none
+func runningTimer() *timer.Timer {
+	t := loadTimer()
+	if t == nil {
+		fmt.Println("No timer is running, start one with 'jtl start <ticket>'")
+		os.Exit(1)
+	}
+	return t
+}
+
+func saveTimer(t *timer.Timer) {
+	if err := t.Save(timer.DefaultPath()); err != nil {
+		fmt.Println("Error saving timer:", err)
+		os.Exit(1)
+	}
+}
+
+func clearTimer() {
+	if err := timer.Clear(timer.DefaultPath()); err != nil {
+		fmt.Println("Error clearing timer:", err)
+		os.Exit(1)
+	}
+}
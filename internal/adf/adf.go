@@ -0,0 +1,163 @@
+package adf
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Node is a single node of Atlassian Document Format tree
+type Node struct {
+	Type    string         `json:"type"`
+	Version int            `json:"version,omitempty"`
+	Text    string         `json:"text,omitempty"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+	Marks   []Mark         `json:"marks,omitempty"`
+	Content []*Node        `json:"content,omitempty"`
+}
+
+// Mark is a text formatting, e.g. code or strong
+type Mark struct {
+	Type string `json:"type"`
+}
+
+var (
+	bulletItem  = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	orderedItem = regexp.MustCompile(`^\s*\d+[.)]\s+(.*)$`)
+	inlineCode  = regexp.MustCompile("`([^`]+)`")
+)
+
+// FromText converts a plain text with simple markdown into an Atlassian Document Format document, required by Jira REST API v3.
+// Supported are paragraphs (separated by blank lines), line breaks, bullet and ordered lists,
+// fenced code blocks and inline code.
+func FromText(text string) *Node {
+	doc := &Node{Type: "doc", Version: 1}
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	var paragraph, list *Node
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case strings.HasPrefix(strings.TrimSpace(line), "```"):
+			paragraph, list = nil, nil
+			block := &Node{Type: "codeBlock"}
+			if lang := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "```")); lang != "" {
+				block.Attrs = map[string]any{"language": lang}
+			}
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			if len(code) > 0 {
+				block.Content = []*Node{{Type: "text", Text: strings.Join(code, "\n")}}
+			}
+			doc.Content = append(doc.Content, block)
+		case strings.TrimSpace(line) == "":
+			paragraph, list = nil, nil
+		case bulletItem.MatchString(line) || orderedItem.MatchString(line):
+			paragraph = nil
+			listType, item := "bulletList", bulletItem.FindStringSubmatch(line)
+			if item == nil {
+				listType, item = "orderedList", orderedItem.FindStringSubmatch(line)
+			}
+			if list == nil || list.Type != listType {
+				list = &Node{Type: listType}
+				doc.Content = append(doc.Content, list)
+			}
+			list.Content = append(list.Content, &Node{
+				Type:    "listItem",
+				Content: []*Node{{Type: "paragraph", Content: inline(item[1])}},
+			})
+		default:
+			list = nil
+			if paragraph == nil {
+				paragraph = &Node{Type: "paragraph"}
+				doc.Content = append(doc.Content, paragraph)
+			} else {
+				paragraph.Content = append(paragraph.Content, &Node{Type: "hardBreak"})
+			}
+			paragraph.Content = append(paragraph.Content, inline(line)...)
+		}
+	}
+	if len(doc.Content) == 0 {
+		// document content is required, an empty paragraph keeps it valid
+		doc.Content = append(doc.Content, &Node{Type: "paragraph"})
+	}
+	return doc
+}
+
+// inline splits a line into text nodes, marking `quoted` parts as code
+func inline(line string) []*Node {
+	var nodes []*Node
+	last := 0
+	for _, m := range inlineCode.FindAllStringSubmatchIndex(line, -1) {
+		if m[0] > last {
+			nodes = append(nodes, &Node{Type: "text", Text: line[last:m[0]]})
+		}
+		nodes = append(nodes, &Node{Type: "text", Text: line[m[2]:m[3]], Marks: []Mark{{Type: "code"}}})
+		last = m[1]
+	}
+	if last < len(line) {
+		nodes = append(nodes, &Node{Type: "text", Text: line[last:]})
+	}
+	return nodes
+}
+
+// ToText converts an ADF document back into a plain text, the reverse of FromText.
+// Unknown nodes are rendered by their text content.
+func ToText(doc *Node) string {
+	if doc == nil {
+		return ""
+	}
+	var blocks []string
+	for _, n := range doc.Content {
+		blocks = append(blocks, blockText(n))
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+func blockText(n *Node) string {
+	switch n.Type {
+	case "codeBlock":
+		lang, _ := n.Attrs["language"].(string)
+		return "```" + lang + "\n" + inlineText(n) + "\n```"
+	case "bulletList", "orderedList":
+		var items []string
+		for i, item := range n.Content {
+			prefix := "- "
+			if n.Type == "orderedList" {
+				prefix = strconv.Itoa(i+1) + ". "
+			}
+			var text []string
+			for _, c := range item.Content {
+				text = append(text, blockText(c))
+			}
+			items = append(items, prefix+strings.Join(text, "\n"))
+		}
+		return strings.Join(items, "\n")
+	default:
+		return inlineText(n)
+	}
+}
+
+func inlineText(n *Node) string {
+	switch n.Type {
+	case "text":
+		for _, m := range n.Marks {
+			if m.Type == "code" && n.Text != "" {
+				return "`" + n.Text + "`"
+			}
+		}
+		return n.Text
+	case "hardBreak":
+		return "\n"
+	}
+	sb := strings.Builder{}
+	for _, c := range n.Content {
+		if n.Type == "codeBlock" {
+			sb.WriteString(c.Text)
+			continue
+		}
+		sb.WriteString(inlineText(c))
+	}
+	return sb.String()
+}
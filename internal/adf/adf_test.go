@@ -0,0 +1,72 @@
+package adf
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			"Should wrap a single line into a paragraph",
+			"Some repeating meeting!",
+			`{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"Some repeating meeting!"}]}]}`,
+		},
+		{
+			"Should keep line breaks and split paragraphs by blank lines",
+			"line 1\nline 2\n\nline 3",
+			`{"type":"doc","version":1,"content":[` +
+				`{"type":"paragraph","content":[{"type":"text","text":"line 1"},{"type":"hardBreak"},{"type":"text","text":"line 2"}]},` +
+				`{"type":"paragraph","content":[{"type":"text","text":"line 3"}]}]}`,
+		},
+		{
+			"Should convert bullets into a list",
+			"Done:\n- review\n* deploy",
+			`{"type":"doc","version":1,"content":[` +
+				`{"type":"paragraph","content":[{"type":"text","text":"Done:"}]},` +
+				`{"type":"bulletList","content":[` +
+				`{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"review"}]}]},` +
+				`{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"deploy"}]}]}]}]}`,
+		},
+		{
+			"Should convert fenced and inline code",
+			"run `make test`\n```sh\nmake\nmake install\n```",
+			`{"type":"doc","version":1,"content":[` +
+				`{"type":"paragraph","content":[{"type":"text","text":"run "},{"type":"text","text":"make test","marks":[{"type":"code"}]}]},` +
+				`{"type":"codeBlock","attrs":{"language":"sh"},"content":[{"type":"text","text":"make\nmake install"}]}]}`,
+		},
+		{
+			"Should produce an empty document for empty text",
+			"",
+			`{"type":"doc","version":1,"content":[{"type":"paragraph"}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(FromText(tt.text))
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestToText(t *testing.T) {
+	texts := []string{
+		"Some repeating meeting!",
+		"line 1\nline 2\n\nline 3",
+		"Done:\n\n- review\n- deploy",
+		"Steps:\n\n1. build\n2. run `make test`",
+		"```sh\nmake\nmake install\n```",
+	}
+	for _, text := range texts {
+		t.Run(text, func(t *testing.T) {
+			assert.Equal(t, text, ToText(FromText(text)))
+		})
+	}
+}
@@ -15,17 +15,25 @@ import (
 const (
 	DefaultDateTimePattern = "02 Jan 2006 15:04"
 	DefaultDatePattern     = "02 Jan 2006"
-	DataFileHeader         = "id,date,activity,hours,jira"
+	JiraDateTimePattern    = "2006-01-02T15:04:05.000-0700"
+	DefaultAPIVersion      = "2"
+	DataFileHeader         = "id,date,activity,hours,jira,visibility"
+	DefaultPickJQL         = "assignee = currentUser() AND resolution = Unresolved ORDER BY updated DESC"
 )
 
 var (
-	cfgFile               string
-	dataFile              string
-	now                   time.Time = time.Now()
-	DefaultDayStart                 = time.Date(now.Year(), now.Month(), now.Day(), 8, 45, 0, 0, time.Local).Format(DefaultDateTimePattern)
-	DefaultTicketDuration           = "4h"
+	cfgFile                string
+	dataFile               string
+	DefaultTicketDuration  = "4h"
+	DefaultPushConcurrency = 4
+	DefaultClosedStatuses  = []string{"Closed", "Done", "Resolved"}
 )
 
+// Dir returns jtl home directory $HOME/.jtl
+func Dir() string {
+	return path.Join(homeDir(), ".jtl")
+}
+
 func DataFilePath() string {
 	return dataFile
 }
@@ -57,11 +65,27 @@ func Init() {
 		viper.SetConfigType(configType)
 
 		viper.SetDefault("host", "")
+		viper.SetDefault("apiVersion", DefaultAPIVersion)
 		viper.SetDefault("credentials", map[string]string{
 			"username": "",
 			"password": "",
+			"token":    "",
 		})
 		viper.SetDefault("DateTimePattern", DefaultDateTimePattern)
+		viper.SetDefault("push.concurrency", DefaultPushConcurrency)
+		viper.SetDefault("push.rateLimit", 5)
+		viper.SetDefault("push.retry.maxAttempts", 3)
+		viper.SetDefault("push.retry.baseDelay", "500ms")
+		viper.SetDefault("push.retry.maxDelay", "30s")
+		viper.SetDefault("push.validate", true)
+		viper.SetDefault("push.closedStatuses", DefaultClosedStatuses)
+		viper.SetDefault("issues.ttl", "24h")
+		viper.SetDefault("issues.epicField", "")
+		viper.SetDefault("pick.jql", DefaultPickJQL)
+		viper.SetDefault("http.timeout", "30s")
+		viper.SetDefault("timer.rounding", "nearest:5m")
+		viper.SetDefault("schedule.dayStart", DefaultDayStart)
+		viper.SetDefault("schedule.day", DefaultDayLength)
 
 		if !fileExists(configFullPath) {
 			fmt.Println("Config file not found. Initializing default config:", configFullPath)
@@ -76,11 +100,16 @@ func Init() {
 		fmt.Println("Error reading config file", err)
 
 	}
+	if _, err := LoadSchedule(); err != nil {
+		fmt.Println("Error reading work schedule:", err)
+		os.Exit(1)
+	}
+	ResetSchedule()
 }
 
 func InitDataFile() {
 	createNewDataFile := func() {
-		dataDir := path.Join(homeDir(), ".jtl", "data")
+		dataDir := path.Join(Dir(), "data")
 		f := path.Join(dataDir, GenerateDataFileName())
 		createDirIfNotExists(dataDir)
 		createFileIfNotExists(f)
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SetInFile sets the value by the path of keys in the YAML config file, creating missing maps.
+// Unlike viper.WriteConfig, it keeps comments, order and case of keys, and doesn't write defaults.
+func SetInFile(file string, path []string, value any) error {
+	return editFile(file, func(root *yaml.Node) error {
+		node := root
+		for i, key := range path {
+			if node.Kind != yaml.MappingNode {
+				return fmt.Errorf("%v is not a map", strings.Join(path[:i], "."))
+			}
+			child := mapValue(node, key)
+			if child != nil && child.Tag == "!!null" && i < len(path)-1 {
+				child.Kind, child.Tag, child.Value = yaml.MappingNode, "!!map", ""
+			}
+			if child == nil {
+				child = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, child)
+			}
+			node = child
+		}
+		encoded := &yaml.Node{}
+		if err := encoded.Encode(value); err != nil {
+			return err
+		}
+		// replace the content, but keep comments of the node
+		node.Kind, node.Tag, node.Value, node.Content, node.Style = encoded.Kind, encoded.Tag, encoded.Value, encoded.Content, encoded.Style
+		return nil
+	})
+}
+
+// RemoveFromFile removes the key by the path of keys from the YAML config file. Returns false if it's not found.
+func RemoveFromFile(file string, path []string) (bool, error) {
+	removed := false
+	err := editFile(file, func(root *yaml.Node) error {
+		node := root
+		for _, key := range path[:len(path)-1] {
+			if node = mapValue(node, key); node == nil {
+				return nil
+			}
+		}
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		last := path[len(path)-1]
+		for i := 0; i < len(node.Content); i += 2 {
+			if strings.EqualFold(node.Content[i].Value, last) {
+				node.Content = append(node.Content[:i], node.Content[i+2:]...)
+				removed = true
+				return nil
+			}
+		}
+		return nil
+	})
+	return removed, err
+}
+
+// mapValue returns the value of the key in the mapping node, case-insensitive like viper keys
+func mapValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if strings.EqualFold(node.Content[i].Value, key) {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func editFile(file string, edit func(root *yaml.Node) error) error {
+	data, err := os.ReadFile(file)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal(data, doc); err != nil {
+		return fmt.Errorf("cannot parse %v: %w", file, err)
+	}
+	if doc.Kind == 0 {
+		doc.Kind = yaml.DocumentNode
+	}
+	if len(doc.Content) == 0 {
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return fmt.Errorf("%v is not a map of settings", file)
+	}
+	if err := edit(doc.Content[0]); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	enc.Close()
+	return os.WriteFile(file, buf.Bytes(), 0644)
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEditFile(t *testing.T) {
+	file := path.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(file, []byte("host: https://jira.example.com\n# ticket aliases\nalias:\n  jt1: JIRATICKET-1\n"), 0644)
+
+	t.Run("Should set values keeping comments", func(t *testing.T) {
+		assert.NoError(t, SetInFile(file, []string{"alias", "standup"}, map[string]string{"ticket": "MEET-7", "time": "15m"}))
+		assert.NoError(t, SetInFile(file, []string{"alias", "JT1"}, "JIRATICKET-2"))
+
+		data, _ := os.ReadFile(file)
+		assert.Equal(t, "host: https://jira.example.com\n# ticket aliases\nalias:\n  jt1: JIRATICKET-2\n  standup:\n    ticket: MEET-7\n    time: 15m\n", string(data))
+	})
+
+	t.Run("Should remove keys", func(t *testing.T) {
+		removed, err := RemoveFromFile(file, []string{"alias", "standup"})
+		assert.NoError(t, err)
+		assert.True(t, removed)
+
+		removed, err = RemoveFromFile(file, []string{"alias", "missing"})
+		assert.NoError(t, err)
+		assert.False(t, removed)
+
+		data, _ := os.ReadFile(file)
+		assert.Equal(t, "host: https://jira.example.com\n# ticket aliases\nalias:\n  jt1: JIRATICKET-2\n", string(data))
+	})
+
+	t.Run("Should create missing file and maps", func(t *testing.T) {
+		newFile := path.Join(t.TempDir(), "new.yaml")
+
+		assert.NoError(t, SetInFile(newFile, []string{"alias", "jt1"}, "JIRATICKET-1"))
+
+		data, _ := os.ReadFile(newFile)
+		assert.Equal(t, "alias:\n  jt1: JIRATICKET-1\n", string(data))
+	})
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	DefaultDayStart  = "8:45"
+	DefaultDayLength = "8h"
+)
+
+// Schedule is a work schedule: when working days start and how long they are.
+// Days of the week not set in hours are working days of the day length, except weekends.
+//
+//	schedule:
+//	  dayStart: "9:00"
+//	  day: 6h
+//	  hours:
+//	    mon: 6h
+//	    fri: 0
+type Schedule struct {
+	// DayStart is a time of day, when the work starts
+	DayStart time.Duration
+	// Day is the length of 1d in durations, e.g. 1d 2h
+	Day time.Duration
+	// Hours are targets of the days of the week, 0 for days off
+	Hours map[time.Weekday]time.Duration
+}
+
+var weekdayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// DefaultSchedule is 8h working days from Monday to Friday, starting at 8:45
+func DefaultSchedule() Schedule {
+	s := Schedule{DayStart: 8*time.Hour + 45*time.Minute, Day: 8 * time.Hour, Hours: map[time.Weekday]time.Duration{}}
+	s.setWorkingDays()
+	return s
+}
+
+// setWorkingDays sets hours of Monday to Friday to the day length
+func (s Schedule) setWorkingDays() {
+	for wd := time.Monday; wd <= time.Friday; wd++ {
+		s.Hours[wd] = s.Day
+	}
+}
+
+// LoadSchedule reads the work schedule from config
+func LoadSchedule() (Schedule, error) {
+	s := DefaultSchedule()
+	if v := viper.GetString("schedule.dayStart"); v != "" {
+		t, err := time.Parse("15:04", strings.TrimSpace(v))
+		if err != nil {
+			return s, fmt.Errorf("schedule.dayStart: invalid time %q, expected e.g. 8:45", v)
+		}
+		s.DayStart = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+	if v := viper.GetString("schedule.day"); v != "" {
+		d, err := parseHours(v)
+		if err != nil || d <= 0 || d > 24*time.Hour {
+			return s, fmt.Errorf("schedule.day: invalid duration %q, expected e.g. 8h", v)
+		}
+		s.Day = d
+		s.setWorkingDays()
+	}
+	for key, v := range viper.GetStringMap("schedule.hours") {
+		wd := weekday(key)
+		if wd < 0 {
+			return s, fmt.Errorf("schedule.hours: unknown day %q, expected one of %v", key, strings.Join(weekdayNames, ", "))
+		}
+		d, err := parseHours(fmt.Sprint(v))
+		if err != nil || d < 0 || d > 24*time.Hour {
+			return s, fmt.Errorf("schedule.hours.%v: invalid duration %q, expected e.g. 6h or 0", key, v)
+		}
+		s.Hours[time.Weekday(wd)] = d
+	}
+	return s, nil
+}
+
+var (
+	scheduleOnce sync.Once
+	schedule     Schedule
+)
+
+// WorkSchedule returns the work schedule from config, or the default one if it's not valid.
+// The schedule is loaded once, see ResetSchedule.
+func WorkSchedule() Schedule {
+	scheduleOnce.Do(func() {
+		var err error
+		if schedule, err = LoadSchedule(); err != nil {
+			log.Println("Invalid work schedule, using the default one:", err)
+			schedule = DefaultSchedule()
+		}
+	})
+	return schedule
+}
+
+// ResetSchedule makes WorkSchedule load the schedule again, after the config is read or changed
+func ResetSchedule() {
+	scheduleOnce = sync.Once{}
+}
+
+// Target returns the working hours of the day, 0 if it's a day off
+func (s Schedule) Target(day time.Time) time.Duration {
+	return s.Hours[day.Weekday()]
+}
+
+// IsWorkingDay returns true if the day has working hours
+func (s Schedule) IsWorkingDay(day time.Time) bool {
+	return s.Target(day) > 0
+}
+
+// DayStartOf returns the time of the day when the work starts
+func (s Schedule) DayStartOf(day time.Time) time.Time {
+	y, m, d := day.Date()
+	return time.Date(y, m, d, 0, int(s.DayStart/time.Minute), 0, 0, day.Location())
+}
+
+// parseHours parses a duration like 6h, 7h 30m or 7.5h; a bare number is hours
+func parseHours(v string) (time.Duration, error) {
+	v = strings.ReplaceAll(strings.TrimSpace(v), " ", "")
+	if n, err := strconv.ParseFloat(v, 64); err == nil {
+		return time.Duration(n * float64(time.Hour)), nil
+	}
+	return time.ParseDuration(v)
+}
+
+// weekday returns the day of the week by its name or its first three letters, -1 if it's unknown
+func weekday(name string) int {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for i, short := range weekdayNames {
+		full := strings.ToLower(time.Weekday(i).String())
+		if name == short || name == full {
+			return i
+		}
+	}
+	return -1
+}
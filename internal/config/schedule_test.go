@@ -0,0 +1,80 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadSchedule(t *testing.T) {
+	monday := time.Date(2020, 4, 13, 12, 0, 0, 0, time.Local)
+	t.Cleanup(func() { viper.Set("schedule", nil) })
+
+	t.Run("Should default to 8h working days from Monday to Friday", func(t *testing.T) {
+		s, err := LoadSchedule()
+		assert.NoError(t, err)
+		assert.Equal(t, 8*time.Hour+45*time.Minute, s.DayStart)
+		assert.Equal(t, 8*time.Hour, s.Day)
+		assert.Equal(t, 8*time.Hour, s.Target(monday.AddDate(0, 0, 4)))
+		assert.False(t, s.IsWorkingDay(monday.AddDate(0, 0, 5)))
+	})
+	t.Run("Should read day start, day length and hours per day of the week", func(t *testing.T) {
+		viper.Set("schedule", map[string]any{
+			"daystart": "9:30",
+			"day":      "6h",
+			"hours":    map[string]any{"fri": 0, "Saturday": "2h 30m", "wed": 7.5},
+		})
+		s, err := LoadSchedule()
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2020, 4, 13, 9, 30, 0, 0, time.Local), s.DayStartOf(monday))
+		assert.Equal(t, 6*time.Hour, s.Day)
+		assert.Equal(t, 6*time.Hour, s.Target(monday))
+		assert.Equal(t, 7*time.Hour+30*time.Minute, s.Target(monday.AddDate(0, 0, 2)))
+		assert.False(t, s.IsWorkingDay(monday.AddDate(0, 0, 4)))
+		assert.Equal(t, 2*time.Hour+30*time.Minute, s.Target(monday.AddDate(0, 0, 5)))
+	})
+	for name, schedule := range map[string]map[string]any{
+		"day start":   {"daystart": "late"},
+		"day length":  {"day": "0"},
+		"day of week": {"hours": map[string]any{"someday": "8h"}},
+		"hours":       {"hours": map[string]any{"mon": "25h"}},
+	} {
+		t.Run("Should not read invalid "+name, func(t *testing.T) {
+			viper.Set("schedule", schedule)
+			_, err := LoadSchedule()
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestWorkSchedule(t *testing.T) {
+	t.Cleanup(func() {
+		viper.Set("schedule", nil)
+		ResetSchedule()
+	})
+	viper.Set("schedule", map[string]any{"day": "6h"})
+	ResetSchedule()
+
+	t.Run("Should load schedule once", func(t *testing.T) {
+		assert.Equal(t, 6*time.Hour, WorkSchedule().Day)
+
+		viper.Set("schedule", map[string]any{"day": "7h"})
+
+		assert.Equal(t, 6*time.Hour, WorkSchedule().Day)
+	})
+
+	t.Run("Should reload schedule after reset", func(t *testing.T) {
+		ResetSchedule()
+
+		assert.Equal(t, 7*time.Hour, WorkSchedule().Day)
+	})
+
+	t.Run("Should fall back to default schedule if it's not valid", func(t *testing.T) {
+		viper.Set("schedule", map[string]any{"day": "0"})
+		ResetSchedule()
+
+		assert.Equal(t, DefaultSchedule().Day, WorkSchedule().Day)
+	})
+}
@@ -0,0 +1,188 @@
+package credentials
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/philgal/jtl/internal/model"
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+)
+
+// Provider supplies credentials used to authenticate requests to Jira.
+// A provider may return partially filled credentials, e.g. only a username, leaving the rest to the next provider in a Chain.
+type Provider interface {
+	Credentials() (*model.Credentials, error)
+}
+
+// Default builds the chain of providers from config: plaintext config, password command (if configured) and encrypted file store.
+// If they don't provide valid credentials, the missing ones are prompted.
+func Default() Provider {
+	chain := Chain{Config{}}
+	if command := viper.GetString("credentials.passwordCommand"); command != "" {
+		chain = append(chain, Command{Command: command})
+	}
+	chain = append(chain, NewFileStore(DefaultStorePath()))
+	return Prompt{In: os.Stdin, From: chain}
+}
+
+// Chain asks providers in order, merging fields they return, until credentials are valid for the auth type.
+// If none of them completes the credentials, merged partial credentials are returned.
+type Chain []Provider
+
+func (c Chain) Credentials() (*model.Credentials, error) {
+	creds := &model.Credentials{}
+	for _, p := range c {
+		next, err := p.Credentials()
+		if err != nil {
+			return nil, err
+		}
+		creds = merge(creds, next).Trim()
+		if creds.IsValid() {
+			break
+		}
+	}
+	return creds, nil
+}
+
+// merge fills empty fields of creds with values from other
+func merge(creds, other *model.Credentials) *model.Credentials {
+	if other == nil {
+		return creds
+	}
+	merged := *creds
+	for _, f := range []struct {
+		dst *string
+		src string
+	}{
+		{&merged.Type, other.Type},
+		{&merged.Username, other.Username},
+		{&merged.Password, other.Password},
+		{&merged.Token, other.Token},
+	} {
+		if strings.TrimSpace(*f.dst) == "" {
+			*f.dst = f.src
+		}
+	}
+	return &merged
+}
+
+// Static always returns the given credentials. It's handy for tests, so push can run without prompting.
+type Static struct {
+	Creds model.Credentials
+}
+
+func (s Static) Credentials() (*model.Credentials, error) {
+	creds := s.Creds
+	return &creds, nil
+}
+
+// Config reads plaintext credentials and auth type from config.yaml.
+// The auth type is only returned if it's set explicitly, so that the type saved by 'jtl login' isn't overridden by the default.
+type Config struct{}
+
+func (Config) Credentials() (*model.Credentials, error) {
+	creds := model.Credentials{}
+	if err := viper.UnmarshalKey("credentials", &creds); err != nil {
+		return nil, fmt.Errorf("unable to decode credentials config: %w", err)
+	}
+	if viper.IsSet("auth.type") {
+		creds.Type = viper.GetString("auth.type")
+	}
+	return creds.Trim(), nil
+}
+
+// Command runs an external command, e.g. "pass show jira", and uses the first line of its output as a password or a token, depending on the auth type
+type Command struct {
+	Command string
+}
+
+func (c Command) Credentials() (*model.Credentials, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", c.Command)
+	} else {
+		cmd = exec.Command("sh", "-c", c.Command)
+	}
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("password command %q failed: %w", c.Command, err)
+	}
+	secret, _, _ := strings.Cut(string(out), "\n")
+	return secretFor(viper.GetString("auth.type"), secret), nil
+}
+
+// secretFor puts the secret into the field, used by the auth type
+func secretFor(authType, secret string) *model.Credentials {
+	creds := &model.Credentials{Type: authType}
+	switch creds.AuthType() {
+	case model.AuthBearer, model.AuthCloudToken:
+		creds.Token = strings.TrimSpace(secret)
+	default:
+		creds.Password = strings.TrimSpace(secret)
+	}
+	return creds
+}
+
+// Prompt asks the user to enter credentials required by the auth type, which are not provided by From
+type Prompt struct {
+	In   *os.File
+	From Provider
+}
+
+func (p Prompt) Credentials() (*model.Credentials, error) {
+	creds := model.Credentials{}
+	if p.From != nil {
+		known, err := p.From.Credentials()
+		if err != nil {
+			return nil, err
+		}
+		if known.IsValid() {
+			return known, nil
+		}
+		creds = *known
+	}
+	reader := bufio.NewReader(p.In)
+	switch creds.AuthType() {
+	case model.AuthBearer:
+		creds.Token = p.readSecret("Enter Personal Access Token: ")
+	case model.AuthCloudToken:
+		if creds.Username == "" {
+			creds.Username = readLine(reader, "Enter Email: ")
+		}
+		creds.Token = p.readSecret("Enter API Token: ")
+	default:
+		if creds.Username == "" {
+			creds.Username = readLine(reader, "Enter Username: ")
+		}
+		creds.Password = p.readSecret("Enter Password: ")
+	}
+	return creds.Trim(), nil
+}
+
+func readLine(reader *bufio.Reader, prompt string) string {
+	fmt.Print(prompt)
+	line, err := reader.ReadString('\n')
+	if err != nil && err != io.EOF {
+		log.Println("Error reading input from user: ", err)
+	}
+	return line
+}
+
+func (p Prompt) readSecret(prompt string) string {
+	fmt.Print(prompt)
+	secret, err := term.ReadPassword(int(p.In.Fd()))
+	fmt.Println()
+	if err != nil {
+		fmt.Println("Error reading secret from user: ", err)
+		log.Println("Error reading secret from user: ", err)
+	}
+	return string(secret)
+}
@@ -0,0 +1,128 @@
+package credentials
+
+import (
+	"encoding/hex"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/philgal/jtl/internal/model"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChain_Credentials(t *testing.T) {
+	t.Run("Should merge username and secret from different providers", func(t *testing.T) {
+		chain := Chain{
+			Static{model.Credentials{Type: model.AuthBasic, Username: "user"}},
+			Static{model.Credentials{Username: "ignored", Password: "secret"}},
+			Static{model.Credentials{Password: "never asked"}},
+		}
+
+		creds, err := chain.Credentials()
+
+		assert.NoError(t, err)
+		assert.Equal(t, &model.Credentials{Type: model.AuthBasic, Username: "user", Password: "secret"}, creds)
+	})
+
+	t.Run("Should use auth type of the store, unless it's set in config", func(t *testing.T) {
+		t.Cleanup(viper.Reset)
+		stored := Static{model.Credentials{Type: model.AuthBearer, Token: "pat"}}
+
+		creds, err := Chain{Config{}, stored}.Credentials()
+		assert.NoError(t, err)
+		assert.Equal(t, model.AuthBearer, creds.Type)
+
+		viper.Set("auth.type", model.AuthCloudToken)
+		creds, err = Chain{Config{}, stored}.Credentials()
+		assert.NoError(t, err)
+		assert.Equal(t, model.AuthCloudToken, creds.Type)
+	})
+
+	t.Run("Should return partial credentials if none is complete", func(t *testing.T) {
+		creds, err := Chain{Static{model.Credentials{Type: model.AuthBearer}}}.Credentials()
+
+		assert.NoError(t, err)
+		assert.False(t, creds.IsValid())
+	})
+}
+
+func TestCommand_Credentials(t *testing.T) {
+	t.Cleanup(viper.Reset)
+
+	t.Run("Should use first line of the output as password", func(t *testing.T) {
+		viper.Set("auth.type", model.AuthBasic)
+
+		creds, err := Command{Command: "printf 'secret\\nsecond line'"}.Credentials()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "secret", creds.Password)
+	})
+
+	t.Run("Should use the output as token for bearer auth", func(t *testing.T) {
+		viper.Set("auth.type", model.AuthBearer)
+
+		creds, err := Command{Command: "echo pat-token"}.Credentials()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "pat-token", creds.Token)
+	})
+
+	t.Run("Should fail if command fails", func(t *testing.T) {
+		_, err := Command{Command: "exit 3"}.Credentials()
+
+		assert.Error(t, err)
+	})
+}
+
+func TestFileStore(t *testing.T) {
+	dir := t.TempDir()
+	passphrase := "correct horse"
+	store := FileStore{Path: path.Join(dir, storeFileName), Passphrase: func(bool) (string, error) { return passphrase, nil }}
+
+	t.Run("Should return empty credentials when nothing is stored", func(t *testing.T) {
+		creds, err := store.Credentials()
+
+		assert.NoError(t, err)
+		assert.Equal(t, &model.Credentials{}, creds)
+	})
+
+	t.Run("Should save encrypted credentials and read them back", func(t *testing.T) {
+		os.WriteFile(path.Join(dir, legacyKeyFileName), []byte("old key"), 0600)
+		saved := &model.Credentials{Type: model.AuthCloudToken, Username: "me@example.com", Token: "api-token"}
+
+		assert.NoError(t, store.Save(saved))
+		raw, _ := os.ReadFile(store.Path)
+		creds, err := store.Credentials()
+
+		assert.NoError(t, err)
+		assert.Equal(t, saved, creds)
+		assert.NotContains(t, string(raw), "api-token")
+		files, _ := os.ReadDir(dir)
+		assert.Len(t, files, 1, "no key is kept next to the store")
+	})
+
+	t.Run("Should not read credentials with wrong passphrase", func(t *testing.T) {
+		passphrase = "wrong"
+		t.Cleanup(func() { passphrase = "correct horse" })
+
+		_, err := store.Credentials()
+
+		assert.EqualError(t, err, "cannot decrypt credentials store: wrong passphrase")
+	})
+
+	t.Run("Should remove store on clear", func(t *testing.T) {
+		assert.NoError(t, store.Clear())
+
+		assert.NoFileExists(t, store.Path)
+	})
+}
+
+func TestDeriveKey(t *testing.T) {
+	t.Run("Should derive PBKDF2-HMAC-SHA256 key", func(t *testing.T) {
+		// test vector of RFC 7914, section 11
+		key := deriveKey("passwd", []byte("salt"), 1)
+
+		assert.Equal(t, "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc", hex.EncodeToString(key))
+	})
+}
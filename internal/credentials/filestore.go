@@ -0,0 +1,206 @@
+package credentials
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/philgal/jtl/internal/config"
+	"github.com/philgal/jtl/internal/model"
+	"golang.org/x/term"
+)
+
+const (
+	storeFileName = "credentials.enc"
+	// legacyKeyFileName is the key, which earlier versions kept next to the store. It's removed by login and logout.
+	legacyKeyFileName = "credentials.key"
+	// PassphraseEnv is the environment variable with the passphrase of the store, for pushes without a terminal, e.g. from cron
+	PassphraseEnv = "JTL_PASSPHRASE"
+
+	saltSize      = 16
+	kdfIterations = 600000
+)
+
+// storeMagic starts the store file, followed by the salt, the nonce and the sealed credentials
+var storeMagic = []byte("jtl1")
+
+// DefaultStorePath returns a path of the credentials store file, $HOME/.jtl/credentials.enc
+func DefaultStorePath() string {
+	return path.Join(config.Dir(), storeFileName)
+}
+
+// FileStore keeps credentials in a file, written by 'jtl login' and removed by 'jtl logout'.
+// The file is encrypted with AES-GCM by a key, derived from the user's passphrase with PBKDF2-HMAC-SHA256.
+// Neither the key nor the passphrase is stored: the passphrase is asked when the store is saved and unlocked.
+type FileStore struct {
+	Path string
+	// Passphrase returns the passphrase of the store, confirm is true when the store is saved with a new one
+	Passphrase func(confirm bool) (string, error)
+}
+
+// NewFileStore creates a FileStore, which passphrase is read from JTL_PASSPHRASE or prompted
+func NewFileStore(storePath string) FileStore {
+	return FileStore{Path: storePath, Passphrase: promptPassphrase}
+}
+
+// Credentials returns stored credentials, or empty credentials if nothing is stored
+func (s FileStore) Credentials() (*model.Credentials, error) {
+	sealed, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &model.Credentials{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(sealed, storeMagic) {
+		return nil, errors.New("credentials store is written by an earlier version, run 'jtl login' again")
+	}
+	sealed = sealed[len(storeMagic):]
+	if len(sealed) < saltSize {
+		return nil, errors.New("credentials store is corrupted, run 'jtl login' again")
+	}
+	passphrase, err := s.Passphrase(false)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(passphrase, sealed[:saltSize])
+	if err != nil {
+		return nil, err
+	}
+	sealed = sealed[saltSize:]
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("credentials store is corrupted, run 'jtl login' again")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, errors.New("cannot decrypt credentials store: wrong passphrase")
+	}
+	creds := &model.Credentials{}
+	if err := json.Unmarshal(plaintext, creds); err != nil {
+		return nil, err
+	}
+	return creds, nil
+}
+
+// Save encrypts and writes credentials into the store with a new salt
+func (s FileStore) Save(creds *model.Credentials) error {
+	if err := os.MkdirAll(path.Dir(s.Path), 0700); err != nil {
+		return err
+	}
+	passphrase, err := s.Passphrase(true)
+	if err != nil {
+		return err
+	}
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return err
+	}
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return err
+	}
+	plaintext, err := json.Marshal(creds)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	sealed := slices.Concat(storeMagic, salt, nonce)
+	if err := os.WriteFile(s.Path, gcm.Seal(sealed, nonce, plaintext, nil), 0600); err != nil {
+		return err
+	}
+	return s.removeLegacyKey()
+}
+
+// Clear removes stored credentials
+func (s FileStore) Clear() error {
+	err := os.Remove(s.Path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return s.removeLegacyKey()
+}
+
+// removeLegacyKey removes the key, which earlier versions kept next to the store
+func (s FileStore) removeLegacyKey() error {
+	err := os.Remove(path.Join(path.Dir(s.Path), legacyKeyFileName))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func newGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(deriveKey(passphrase, salt, kdfIterations))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// deriveKey derives a 32 bytes key from the passphrase with PBKDF2-HMAC-SHA256 (RFC 8018).
+// The key is as long as the hash, so it's a single block of PBKDF2.
+func deriveKey(passphrase string, salt []byte, iterations int) []byte {
+	prf := hmac.New(sha256.New, []byte(passphrase))
+	prf.Write(salt)
+	prf.Write([]byte{0, 0, 0, 1})
+	u := prf.Sum(nil)
+	key := slices.Clone(u)
+	for i := 1; i < iterations; i++ {
+		prf.Reset()
+		prf.Write(u)
+		u = prf.Sum(u[:0])
+		for j := range key {
+			key[j] ^= u[j]
+		}
+	}
+	return key
+}
+
+// promptPassphrase reads the passphrase from JTL_PASSPHRASE or asks the user, twice if it's a new one
+func promptPassphrase(confirm bool) (string, error) {
+	if passphrase := os.Getenv(PassphraseEnv); passphrase != "" {
+		return passphrase, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("stdin is not a terminal, set %v to unlock stored credentials", PassphraseEnv)
+	}
+	read := func(prompt string) (string, error) {
+		fmt.Print(prompt)
+		passphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Println()
+		return strings.TrimSpace(string(passphrase)), err
+	}
+	if !confirm {
+		return read("Enter passphrase of stored credentials: ")
+	}
+	passphrase, err := read("Enter new passphrase of stored credentials: ")
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", errors.New("passphrase is required")
+	}
+	repeated, err := read("Repeat passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if repeated != passphrase {
+		return "", errors.New("passphrases don't match")
+	}
+	return passphrase, nil
+}
@@ -1,11 +1,16 @@
 package csv
 
 import (
+	"crypto/sha256"
 	ecsv "encoding/csv"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"log"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/philgal/jtl/internal/config"
@@ -25,13 +30,15 @@ type File struct {
 // Record represents a single record in a CSV file
 type Record struct {
 	_idx      int
-	ID        string
-	StartedTs string
-	Comment   string
+	ID        string `json:"id"`
+	StartedTs string `json:"started"`
+	Comment   string `json:"comment"`
 	// TimeSpent string `validate:"required"`
 	// Ticket    string `validate:"required"`
-	TimeSpent string `validate:"required,timespent"`
-	Ticket    string `validate:"required,jiraticket"`
+	TimeSpent string `json:"timeSpent" validate:"required,timespent"`
+	Ticket    string `json:"ticket" validate:"required,jiraticket"`
+	// Visibility restricts who can see the worklog, e.g. "role:Developers" or "group:jira-users". Empty means visible to all.
+	Visibility string `json:"visibility,omitempty" validate:"visibility"`
 }
 
 // GetIdx returns a row's index in CSV file
@@ -41,7 +48,29 @@ func (r Record) GetIdx() int {
 
 // AsRow represents a CSV-writable row
 func (r Record) AsRow() []string {
-	return []string{r.ID, r.StartedTs, r.Comment, r.TimeSpent, r.Ticket}
+	return []string{r.ID, r.StartedTs, r.Comment, r.TimeSpent, r.Ticket, r.Visibility}
+}
+
+// hashedDay is the length of 1d in hashed time spent
+const hashedDay = 8 * time.Hour
+
+// Hash returns a hash of the record's content, pushed to Jira: ticket, started, time spent, comment and visibility.
+// Time spent is hashed by duration, so rewriting "90m" as "1h 30m" doesn't change the hash.
+// Days are hashed as 8h regardless of the schedule, so changing schedule.day doesn't change hashes of pushed records.
+// Empty visibility is not hashed, so records pushed before it was supported keep their hashes.
+func (r Record) Hash() string {
+	fields := []string{
+		strings.TrimSpace(r.Ticket),
+		strings.TrimSpace(r.StartedTs),
+		strconv.Itoa(duration.ToMinutesOfDay(r.TimeSpent, hashedDay)),
+		strings.TrimSpace(r.Comment),
+	}
+	if v := strings.TrimSpace(r.Visibility); v != "" {
+		fields = append(fields, v)
+	}
+	content := strings.Join(fields, "\x00")
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
 }
 
 // IsPushed returns true is the item has been already pushed to Jira server
@@ -83,6 +112,15 @@ func (f *File) UpdateRecord(rec Record) {
 	log.Printf("Updated CSV Record: %v\n", rec)
 }
 
+// RemoveRecord removes the record at the given index, shifting indexes of the following records
+func (f *File) RemoveRecord(idx int) {
+	f.Records = slices.Delete(f.Records, idx, idx+1)
+	for i := idx; i < len(f.Records); i++ {
+		f.Records[i]._idx = i
+	}
+	log.Printf("Removed CSV Record #%v\n", idx)
+}
+
 // ReadAll reads CSV file from disk with all records
 func (f *File) ReadAll() {
 	f.Read(func(cr Record) bool { return true })
@@ -96,6 +134,8 @@ func (f *File) Read(recordFilter func(Record) bool) {
 		log.Fatal(err)
 	}
 	reader := ecsv.NewReader(fcsv)
+	// files written before visibility column was added have less fields
+	reader.FieldsPerRecord = -1
 	//Read header to skip it. Maybe later add a param like "readHeader: bool"
 	header, err := reader.Read()
 	if err != nil {
@@ -125,6 +165,10 @@ func (f *File) Write() {
 	}
 	writer := ecsv.NewWriter(fcsv)
 	writer.Comma = ','
+	if len(f.Header) < len(config.Header()) {
+		// upgrade header of files, written before new columns were added
+		f.Header = config.Header()
+	}
 	hErr := writer.Write(f.Header)
 	for _, r := range f.Records {
 		row := r.AsRow()
@@ -166,11 +210,15 @@ var SameDateRecordsFilter = func(logDate time.Time) func(rec Record) bool {
 }
 
 func newCsvRec(rec []string) Record {
-	return Record{
+	r := Record{
 		ID:        rec[0],
 		StartedTs: rec[1],
 		Comment:   rec[2],
 		TimeSpent: rec[3],
 		Ticket:    rec[4],
 	}
+	if len(rec) > 5 {
+		r.Visibility = rec[5]
+	}
+	return r
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/philgal/jtl/internal/config"
 
 	"github.com/philgal/jtl/internal/validation"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -68,6 +69,20 @@ func TestCsvFile_Write(t *testing.T) {
 		assert := assert.New(t)
 		assert.ElementsMatch(writtenFile.Records[0].AsRow(), readFile.Records[0].AsRow())
 	})
+	t.Run("Should upgrade header of an older file and keep visibility", func(t *testing.T) {
+		writtenFile := File{
+			Path:    path,
+			Header:  []string{"id", "date", "activity", "hours", "jira"},
+			Records: []Record{{StartedTs: "14 Apr 2020 11:30", Comment: "Internal", TimeSpent: "10m", Ticket: "TICKET-1", Visibility: "role:Developers"}},
+		}
+		writtenFile.Write()
+
+		readFile := NewCsvFile(writtenFile.Path)
+		readFile.ReadAll()
+
+		assert.Equal(t, config.Header(), readFile.Header)
+		assert.Equal(t, "role:Developers", readFile.Records[0].Visibility)
+	})
 }
 
 func TestCsvFile_ReadAll(t *testing.T) {
@@ -79,8 +94,9 @@ func TestCsvFile_ReadAll(t *testing.T) {
 		{
 			name: "Should reads all rows from not empty file",
 			file: File{
-				Path:   "./csv_testdata/not_empty.csv",
-				Header: config.Header(),
+				Path: "./csv_testdata/not_empty.csv",
+				// written before visibility column was added
+				Header: []string{"id", "date", "activity", "hours", "jira"},
 				Records: []Record{
 					{_idx: 0, ID: "1", StartedTs: "14 Apr 2020 11:30", Comment: "Row with ID", TimeSpent: "10m", Ticket: "TICKET-1"},
 					{_idx: 1, ID: "", StartedTs: "15 Apr 2020 11:30", Comment: "Row without ID", TimeSpent: "10m", Ticket: "TICKET-2"},
@@ -143,3 +159,43 @@ func TestNewCsvRecord(t *testing.T) {
 		})
 	}
 }
+
+func TestCsvFile_RemoveRecord(t *testing.T) {
+	t.Run("Should remove the record and shift indexes of the following ones", func(t *testing.T) {
+		f := File{}
+		for _, ticket := range []string{"TICKET-1", "TICKET-2", "TICKET-3"} {
+			f.AddRecord(Record{Ticket: ticket})
+		}
+		f.RemoveRecord(1)
+
+		assert.Len(t, f.Records, 2)
+		assert.Equal(t, "TICKET-3", f.Records[1].Ticket)
+		assert.Equal(t, 1, f.Records[1].GetIdx())
+	})
+}
+
+func TestRecord_Hash(t *testing.T) {
+	t.Cleanup(func() {
+		viper.Set("schedule.day", nil)
+		config.ResetSchedule()
+	})
+	rec := Record{ID: "1", StartedTs: "14 Apr 2020 11:30", Comment: "Workshop", TimeSpent: "1d 2h", Ticket: "TICKET-1"}
+
+	t.Run("Should not change hash when schedule day changes", func(t *testing.T) {
+		viper.Set("schedule.day", "8h")
+		config.ResetSchedule()
+		hash := rec.Hash()
+
+		viper.Set("schedule.day", "6h")
+		config.ResetSchedule()
+
+		assert.Equal(t, hash, rec.Hash())
+	})
+
+	t.Run("Should not change hash when time spent is rewritten", func(t *testing.T) {
+		rewritten := rec
+		rewritten.TimeSpent = "10h"
+
+		assert.Equal(t, rec.Hash(), rewritten.Hash())
+	})
+}
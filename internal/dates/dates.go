@@ -0,0 +1,177 @@
+package dates
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/philgal/jtl/internal/config"
+)
+
+// Parser parses date expressions relative to the current time of its clock:
+//
+//	now, today, yesterday, tomorrow          - the day, at the day start unless the time is set
+//	mon, tuesday, ...                        - the last such day, today included
+//	-2d, +1d, -1w                            - days or weeks from today
+//	14:00, 9:30, 9am, 2:30pm                 - the time of today, or of the day set before it
+//	2020-04-14T10:00, 2020-04-14T10:00+02:00 - an ISO 8601 date and time, or a date
+//	14 Apr 2020 10:00, 14 Apr 2020           - config.DefaultDateTimePattern or config.DefaultDatePattern
+//
+// A day and a time are combined, e.g. "mon 9:30", "-2d 14:00" or "yesterday at 17:00". Words are case-insensitive.
+type Parser struct {
+	// Now is the clock, times are parsed in its location
+	Now func() time.Time
+	// DayStart is a time of day of expressions without the time, e.g. yesterday
+	DayStart time.Duration
+}
+
+// NewParser returns a parser of date expressions relative to the current time, starting days at schedule.dayStart
+func NewParser() Parser {
+	return Parser{Now: time.Now, DayStart: config.WorkSchedule().DayStart}
+}
+
+// Parse parses a date expression relative to the current time, see Parser
+func Parse(expr string) (time.Time, error) {
+	return NewParser().Parse(expr)
+}
+
+// ParseDay parses a date expression relative to the current time into the start of its day, see Parser
+func ParseDay(expr string) (time.Time, error) {
+	return NewParser().ParseDay(expr)
+}
+
+var (
+	isoLayouts = []string{time.RFC3339, "2006-01-02T15:04Z07:00", "2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04"}
+	offsetRe   = regexp.MustCompile(`^([+-]\d+)([dw])$`)
+	clockRe    = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)?$`)
+	weekdays   = []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+)
+
+// Parse parses the date expression into a time
+func (p Parser) Parse(expr string) (time.Time, error) {
+	expr = strings.TrimSpace(expr)
+	now := p.Now()
+	loc := now.Location()
+	for _, layout := range isoLayouts {
+		if t, err := time.ParseInLocation(layout, strings.ToUpper(expr), loc); err == nil {
+			return t.In(loc), nil
+		}
+	}
+	if t, err := time.ParseInLocation(config.DefaultDateTimePattern, expr, loc); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{config.DefaultDatePattern, time.DateOnly} {
+		if t, err := time.ParseInLocation(layout, expr, loc); err == nil {
+			return at(t, p.DayStart), nil
+		}
+	}
+	fields := strings.Fields(strings.ToLower(expr))
+	if len(fields) == 1 && fields[0] == "now" {
+		return now, nil
+	}
+	var day *time.Time
+	var clock *time.Duration
+	for _, field := range fields {
+		if field == "at" {
+			continue
+		}
+		if d, ok := parseDayOf(field, now); ok && day == nil {
+			day = &d
+		} else if c, ok := parseClock(field); ok && clock == nil {
+			clock = &c
+		} else {
+			return time.Time{}, invalid(expr)
+		}
+	}
+	if day == nil && clock == nil {
+		return time.Time{}, invalid(expr)
+	}
+	if day == nil {
+		today := startOfDay(now)
+		day = &today
+	}
+	if clock == nil {
+		dayStart := p.DayStart
+		clock = &dayStart
+	}
+	return at(*day, *clock), nil
+}
+
+// ParseDay parses the date expression into the start of its day
+func (p Parser) ParseDay(expr string) (time.Time, error) {
+	t, err := p.Parse(expr)
+	if err != nil {
+		return t, err
+	}
+	return startOfDay(t), nil
+}
+
+// parseDayOf parses a day word or an offset in days or weeks into the start of the day relative to now
+func parseDayOf(field string, now time.Time) (time.Time, bool) {
+	today := startOfDay(now)
+	switch field {
+	case "today", "now":
+		return today, true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), true
+	}
+	if m := offsetRe.FindStringSubmatch(field); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		if m[2] == "w" {
+			n *= 7
+		}
+		return today.AddDate(0, 0, n), true
+	}
+	if len(field) >= 3 {
+		for wd, name := range weekdays {
+			if strings.HasPrefix(name, field) {
+				back := (int(today.Weekday()) - wd + 7) % 7
+				return today.AddDate(0, 0, -back), true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseClock parses a time of day, e.g. 14:00, 9am or 2:30pm
+func parseClock(field string) (time.Duration, bool) {
+	m := clockRe.FindStringSubmatch(field)
+	if m == nil || (m[2] == "" && m[3] == "") {
+		return 0, false
+	}
+	h, _ := strconv.Atoi(m[1])
+	min, _ := strconv.Atoi(m[2])
+	switch m[3] {
+	case "am", "pm":
+		if h < 1 || h > 12 {
+			return 0, false
+		}
+		h %= 12
+		if m[3] == "pm" {
+			h += 12
+		}
+	}
+	if h > 23 || min > 59 {
+		return 0, false
+	}
+	return time.Duration(h)*time.Hour + time.Duration(min)*time.Minute, true
+}
+
+// at returns the time of the day, keeping the wall clock on days of DST changes
+func at(day time.Time, clock time.Duration) time.Time {
+	y, m, d := day.Date()
+	return time.Date(y, m, d, 0, int(clock/time.Minute), 0, 0, day.Location())
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+func invalid(expr string) error {
+	return fmt.Errorf("invalid date %q, expected e.g. yesterday, \"mon 9:30\", \"-2d 14:00\", 14:00, 2020-04-14T10:00 or \"14 Apr 2020 10:00\"", expr)
+}
@@ -0,0 +1,64 @@
+package dates
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_Parse(t *testing.T) {
+	loc := time.FixedZone("CEST", 2*60*60)
+	// Wednesday
+	now := time.Date(2020, 4, 15, 16, 20, 0, 0, loc)
+	p := Parser{Now: func() time.Time { return now }, DayStart: 8*time.Hour + 45*time.Minute}
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"now", now},
+		{"today", time.Date(2020, 4, 15, 8, 45, 0, 0, loc)},
+		{"yesterday", time.Date(2020, 4, 14, 8, 45, 0, 0, loc)},
+		{"Yesterday at 17:00", time.Date(2020, 4, 14, 17, 0, 0, 0, loc)},
+		{"tomorrow 9am", time.Date(2020, 4, 16, 9, 0, 0, 0, loc)},
+		{"14:00", time.Date(2020, 4, 15, 14, 0, 0, 0, loc)},
+		{"2:30pm", time.Date(2020, 4, 15, 14, 30, 0, 0, loc)},
+		{"12am", time.Date(2020, 4, 15, 0, 0, 0, 0, loc)},
+		{"mon 9:30", time.Date(2020, 4, 13, 9, 30, 0, 0, loc)},
+		{"wednesday", time.Date(2020, 4, 15, 8, 45, 0, 0, loc)},
+		{"thu", time.Date(2020, 4, 9, 8, 45, 0, 0, loc)},
+		{"14:00 mon", time.Date(2020, 4, 13, 14, 0, 0, 0, loc)},
+		{"-2d 14:00", time.Date(2020, 4, 13, 14, 0, 0, 0, loc)},
+		{"+1d", time.Date(2020, 4, 16, 8, 45, 0, 0, loc)},
+		{"-1w 10:15", time.Date(2020, 4, 8, 10, 15, 0, 0, loc)},
+		{"2020-04-14T10:00", time.Date(2020, 4, 14, 10, 0, 0, 0, loc)},
+		{"2020-04-14 10:00", time.Date(2020, 4, 14, 10, 0, 0, 0, loc)},
+		{"2020-04-14T10:00:00Z", time.Date(2020, 4, 14, 12, 0, 0, 0, loc)},
+		{"2020-04-14", time.Date(2020, 4, 14, 8, 45, 0, 0, loc)},
+		{"14 Apr 2020 10:00", time.Date(2020, 4, 14, 10, 0, 0, 0, loc)},
+		{"14 Apr 2020", time.Date(2020, 4, 14, 8, 45, 0, 0, loc)},
+	}
+	for _, test := range tests {
+		t.Run("Should parse "+test.expr, func(t *testing.T) {
+			got, err := p.Parse(test.expr)
+			assert.NoError(t, err)
+			assert.True(t, test.want.Equal(got), "want %v, got %v", test.want, got)
+		})
+	}
+	for _, expr := range []string{"", "noon", "25:00", "13pm", "9", "mon tue", "14:00 15:00", "14 Apr"} {
+		t.Run("Should not parse "+expr, func(t *testing.T) {
+			_, err := p.Parse(expr)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParser_ParseDay(t *testing.T) {
+	now := time.Date(2020, 4, 15, 16, 20, 0, 0, time.Local)
+	p := Parser{Now: func() time.Time { return now }, DayStart: 8*time.Hour + 45*time.Minute}
+	t.Run("Should parse the start of the day", func(t *testing.T) {
+		got, err := p.ParseDay("yesterday 17:00")
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2020, 4, 14, 0, 0, 0, 0, time.Local), got)
+	})
+}
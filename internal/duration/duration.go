@@ -13,8 +13,6 @@ type Time struct {
 	time.Time
 }
 
-const EightHoursInMin = 8 * 60
-
 func ToString(minutes int) string {
 	if minutes <= 0 {
 		return "0m"
@@ -34,15 +32,20 @@ func ToString(minutes int) string {
 	return sb.String()
 }
 
-// ToMinutes converts string duration d "2D", "4h", "2H 30m", "1d 7h 40m", etc, to minutes.
+// ToMinutes converts string duration d "2D", "4h", "2H 30m", "1d 7h 40m", etc, to minutes. A day is the length of a working day in the schedule.
 // if it fails to process a duration, it returns (-1, error)
 func ToMinutes(d string) int {
+	return ToMinutesOfDay(d, config.WorkSchedule().Day)
+}
+
+// ToMinutesOfDay converts string duration d to minutes like ToMinutes, with a day of the given length
+func ToMinutesOfDay(d string, day time.Duration) int {
 	duration := strings.ToLower(d)
 
 	sub := strings.SplitN(duration, " ", 2)
 	if len(sub) > 1 {
-		v0 := ToMinutes(sub[0])
-		v1 := ToMinutes(sub[1])
+		v0 := ToMinutesOfDay(sub[0], day)
+		v1 := ToMinutesOfDay(sub[1], day)
 		return v0 + v1
 	}
 	//TODO add restrictions for 1h = 60m, ...
@@ -50,7 +53,7 @@ func ToMinutes(d string) int {
 	durationUnit, _ := utf8.DecodeLastRuneInString(duration)
 	switch durationUnit {
 	case 'd':
-		return 8 * 60 * durationValue //consider one working day = 8h
+		return int(day/time.Minute) * durationValue
 	case 'h':
 		return 60 * durationValue
 	case 'm':
@@ -3,6 +3,9 @@ package duration
 import (
 	"testing"
 	"time"
+
+	"github.com/philgal/jtl/internal/config"
+	"github.com/spf13/viper"
 )
 
 func TestDateTimeToDate(t *testing.T) {
@@ -48,3 +51,26 @@ func TestMinutesToDuraion(t *testing.T) {
 		})
 	}
 }
+
+func TestToMinutesWithScheduledDay(t *testing.T) {
+	viper.Set("schedule.day", "6h")
+	config.ResetSchedule()
+	t.Cleanup(func() {
+		viper.Set("schedule.day", nil)
+		config.ResetSchedule()
+	})
+	tests := []struct {
+		duration string
+		want     int
+	}{
+		{"1d", 6 * 60},
+		{"2d 1h 30m", 13*60 + 30},
+	}
+	for _, tt := range tests {
+		t.Run(tt.duration, func(t *testing.T) {
+			if got := ToMinutes(tt.duration); got != tt.want {
+				t.Errorf("ToMinutes(%v) = %v, want %v", tt.duration, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package issues
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/philgal/jtl/internal/config"
+	"github.com/philgal/jtl/internal/jira"
+)
+
+// fields are the issue fields kept in the cache
+var fields = []string{"summary", "status", "project", "issuetype", "parent", "components"}
+
+// Issue is metadata of a Jira issue, kept in the cache
+type Issue struct {
+	Key        string    `json:"key"`
+	Summary    string    `json:"summary,omitempty"`
+	Status     string    `json:"status,omitempty"`
+	Project    string    `json:"project,omitempty"`
+	Epic       string    `json:"epic,omitempty"`
+	Components []string  `json:"components,omitempty"`
+	FetchedAt  time.Time `json:"fetchedAt,omitempty"`
+	UsedAt     time.Time `json:"usedAt,omitempty"`
+}
+
+// Fetcher fetches issues by key, implemented by jira.Client
+type Fetcher interface {
+	Issue(key string, fields ...string) (*jira.Issue, error)
+}
+
+// Cache keeps metadata of issues, fetched from Jira, for reports and completion.
+// Issues fetched more than TTL ago are stale, but still returned until refreshed.
+type Cache struct {
+	Path   string           `json:"-"`
+	TTL    time.Duration    `json:"-"`
+	Issues map[string]Issue `json:"issues"`
+}
+
+// DefaultPath returns path of the cache in jtl home directory
+func DefaultPath() string {
+	return path.Join(config.Dir(), "cache", "issues.json")
+}
+
+// Load reads the cache from disk. A missing file is an empty cache.
+func Load(path string, ttl time.Duration) (*Cache, error) {
+	c := &Cache{Path: path, TTL: ttl, Issues: map[string]Issue{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, err
+	}
+	if c.Issues == nil {
+		c.Issues = map[string]Issue{}
+	}
+	return c, nil
+}
+
+// Save writes the cache to disk, creating its directory
+func (c *Cache) Save() error {
+	if err := os.MkdirAll(filepath.Dir(c.Path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.Path, data, 0644)
+}
+
+// Stale returns keys of issues, which are not cached or fetched more than TTL ago, without duplicates
+func (c *Cache) Stale(keys []string) []string {
+	var stale []string
+	for _, key := range keys {
+		issue, found := c.Issues[key]
+		if (!found || time.Since(issue.FetchedAt) > c.TTL) && !slices.Contains(stale, key) {
+			stale = append(stale, key)
+		}
+	}
+	return stale
+}
+
+// Keys returns keys of all cached issues
+func (c *Cache) Keys() []string {
+	return slices.Sorted(maps.Keys(c.Issues))
+}
+
+// Touch marks the issues as used now, adding unknown ones to be fetched by the next refresh
+func (c *Cache) Touch(keys ...string) {
+	for _, key := range keys {
+		issue, found := c.Issues[key]
+		if !found {
+			issue = Issue{Key: key}
+		}
+		issue.UsedAt = time.Now()
+		c.Issues[key] = issue
+	}
+}
+
+// Summaries returns summaries of cached issues by key
+func (c *Cache) Summaries() map[string]string {
+	summaries := map[string]string{}
+	for key, issue := range c.Issues {
+		if issue.Summary != "" {
+			summaries[key] = issue.Summary
+		}
+	}
+	return summaries
+}
+
+// Recent returns up to n most recently used issues
+func (c *Cache) Recent(n int) []Issue {
+	recent := slices.Collect(maps.Values(c.Issues))
+	slices.SortFunc(recent, func(a, b Issue) int {
+		return cmp.Or(b.UsedAt.Compare(a.UsedAt), strings.Compare(a.Key, b.Key))
+	})
+	return recent[:min(n, len(recent))]
+}
+
+// Refresh fetches the issues, keeping when they were used. The epic is read from epicField, e.g. Epic Link custom field of Jira Server,
+// or from the parent issue if it's an epic. Issues, which can't be fetched, are skipped and reported in the error.
+func (c *Cache) Refresh(fetcher Fetcher, keys []string, epicField string) (int, error) {
+	requested := fields
+	if epicField != "" {
+		requested = append(slices.Clone(fields), epicField)
+	}
+	var errs []error
+	refreshed := 0
+	for _, key := range keys {
+		issue, err := fetcher.Issue(key, requested...)
+		if err != nil {
+			log.Printf("Cannot refresh %v: %v\n", key, err)
+			errs = append(errs, fmt.Errorf("%v: %w", key, err))
+			continue
+		}
+		cached := fromJira(issue, epicField)
+		cached.UsedAt = c.Issues[key].UsedAt
+		c.Issues[key] = cached
+		refreshed++
+	}
+	return refreshed, errors.Join(errs...)
+}
+
+func fromJira(issue *jira.Issue, epicField string) Issue {
+	f := issue.Fields
+	cached := Issue{Key: issue.Key, Summary: f.Summary, FetchedAt: time.Now()}
+	if f.Status != nil {
+		cached.Status = f.Status.Name
+	}
+	if f.Project != nil {
+		cached.Project = f.Project.Key
+	}
+	for _, component := range f.Components {
+		cached.Components = append(cached.Components, component.Name)
+	}
+	if epicField != "" {
+		cached.Epic = f.String(epicField)
+	}
+	if p := f.Parent; cached.Epic == "" && p != nil && p.Fields.IssueType != nil && strings.EqualFold(p.Fields.IssueType.Name, "Epic") {
+		cached.Epic = p.Key
+	}
+	return cached
+}
@@ -0,0 +1,66 @@
+package issues
+
+import (
+	"encoding/json"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/philgal/jtl/internal/jira"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeFetcher map[string]string
+
+func (f fakeFetcher) Issue(key string, fields ...string) (*jira.Issue, error) {
+	body, found := f[key]
+	if !found {
+		return nil, &jira.ErrorCollection{StatusCode: 404}
+	}
+	issue := &jira.Issue{}
+	return issue, json.Unmarshal([]byte(body), issue)
+}
+
+func TestCache(t *testing.T) {
+	fetcher := fakeFetcher{
+		"SUP-1": `{"key":"SUP-1","fields":{"summary":"Support","status":{"name":"Open"},"project":{"key":"SUP"},
+			"components":[{"name":"API"}],"parent":{"key":"SUP-100","fields":{"issuetype":{"name":"Epic"}}}}}`,
+		"DEV-1": `{"key":"DEV-1","fields":{"summary":"Feature","customfield_10008":"DEV-10"}}`,
+	}
+	cachePath := path.Join(t.TempDir(), "cache", "issues.json")
+	c, err := Load(cachePath, time.Hour)
+	assert.NoError(t, err)
+	c.Touch("DEV-1")
+
+	t.Run("Should fetch stale issues with epic from parent or custom field", func(t *testing.T) {
+		assert.Equal(t, []string{"SUP-1", "DEV-1", "GONE-1"}, c.Stale([]string{"SUP-1", "DEV-1", "SUP-1", "GONE-1"}))
+
+		refreshed, err := c.Refresh(fetcher, []string{"SUP-1", "DEV-1", "GONE-1"}, "customfield_10008")
+
+		assert.Equal(t, 2, refreshed)
+		assert.ErrorContains(t, err, "GONE-1")
+		sup := c.Issues["SUP-1"]
+		assert.Equal(t, "Support", sup.Summary)
+		assert.Equal(t, "Open", sup.Status)
+		assert.Equal(t, "SUP", sup.Project)
+		assert.Equal(t, "SUP-100", sup.Epic)
+		assert.Equal(t, []string{"API"}, sup.Components)
+		assert.Equal(t, "DEV-10", c.Issues["DEV-1"].Epic)
+		assert.Empty(t, c.Stale([]string{"SUP-1", "DEV-1"}))
+	})
+
+	t.Run("Should keep when issues were used", func(t *testing.T) {
+		assert.Equal(t, "DEV-1", c.Recent(1)[0].Key)
+		assert.Equal(t, map[string]string{"SUP-1": "Support", "DEV-1": "Feature"}, c.Summaries())
+	})
+
+	t.Run("Should save and load", func(t *testing.T) {
+		assert.NoError(t, c.Save())
+
+		loaded, err := Load(cachePath, time.Hour)
+
+		assert.NoError(t, err)
+		assert.Equal(t, c.Keys(), loaded.Keys())
+		assert.Equal(t, "SUP-100", loaded.Issues["SUP-1"].Epic)
+	})
+}
@@ -0,0 +1,67 @@
+package jira
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+)
+
+// IssueCheck is an outcome of checking that an issue accepts worklogs. Problem is empty if it does.
+type IssueCheck struct {
+	Key     string
+	Summary string
+	Status  string
+	Problem string
+}
+
+// IsValid returns true if worklogs can be logged in the issue
+func (c IssueCheck) IsValid() bool {
+	return c.Problem == ""
+}
+
+// CheckIssues resolves every issue, checking it's not in one of closed statuses and the user may log work in it.
+// Checks are returned in the order of keys, duplicates are checked once.
+func (c *Client) CheckIssues(keys []string, closedStatuses []string) []IssueCheck {
+	var checks []IssueCheck
+	checked := map[string]bool{}
+	for _, key := range keys {
+		if checked[key] {
+			continue
+		}
+		checked[key] = true
+		checks = append(checks, c.checkIssue(key, closedStatuses))
+	}
+	return checks
+}
+
+func (c *Client) checkIssue(key string, closedStatuses []string) IssueCheck {
+	check := IssueCheck{Key: key}
+	issue, err := c.Issue(key, "summary", "status")
+	if IsNotFound(err) {
+		check.Problem = "issue does not exist or is not visible"
+		return check
+	}
+	if err != nil {
+		check.Problem = fmt.Sprintf("cannot resolve issue: %v", err)
+		return check
+	}
+	check.Summary = issue.Fields.Summary
+	if issue.Fields.Status != nil {
+		check.Status = issue.Fields.Status.Name
+	}
+	if slices.ContainsFunc(closedStatuses, func(s string) bool { return strings.EqualFold(strings.TrimSpace(s), check.Status) }) {
+		check.Problem = fmt.Sprintf("issue is %v", check.Status)
+		return check
+	}
+	granted, err := c.MyPermissions(key, PermissionWorkOnIssues)
+	if err != nil {
+		// permissions API is missing on some old servers, the push itself will tell
+		log.Printf("Cannot check permissions in %v: %v\n", key, err)
+		return check
+	}
+	if !granted[PermissionWorkOnIssues] {
+		check.Problem = "you may not log work in the issue"
+	}
+	return check
+}
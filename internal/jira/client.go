@@ -0,0 +1,311 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/philgal/jtl/internal/adf"
+	"github.com/philgal/jtl/internal/config"
+	"github.com/philgal/jtl/internal/model"
+	"github.com/philgal/jtl/internal/rest"
+)
+
+// pageSize is a number of items requested per page of paginated resources
+const pageSize = 100
+
+// Client is a typed client of Jira REST API
+type Client struct {
+	Host        string
+	APIVersion  string
+	Credentials *model.Credentials
+	HTTP        rest.Client
+	Retry       rest.RetryPolicy
+}
+
+// Response describes how a request went, it is returned even if the request failed
+type Response struct {
+	StatusCode int
+	Attempts   int
+}
+
+// NewClient creates a Client for the host, falling back to API v2 if apiVersion is empty
+func NewClient(host, apiVersion string, creds *model.Credentials, httpClient rest.Client) *Client {
+	apiVersion = strings.TrimPrefix(strings.TrimSpace(apiVersion), "v")
+	if apiVersion == "" {
+		apiVersion = config.DefaultAPIVersion
+	}
+	return &Client{
+		Host:        strings.TrimSuffix(host, "/"),
+		APIVersion:  apiVersion,
+		Credentials: creds,
+		HTTP:        httpClient,
+	}
+}
+
+// URL builds a full API URL, e.g. URL("issue/%v/worklog", "JIRA-1")
+func (c *Client) URL(pathFormat string, args ...any) string {
+	escaped := make([]any, len(args))
+	for i, a := range args {
+		escaped[i] = url.PathEscape(fmt.Sprint(a))
+	}
+	return fmt.Sprintf("%v/rest/api/%v/%v", c.Host, c.APIVersion, fmt.Sprintf(pathFormat, escaped...))
+}
+
+// WorklogURL returns URL of the issue's worklogs
+func (c *Client) WorklogURL(issue string) string {
+	return c.URL("issue/%v/worklog", issue)
+}
+
+// NewComment creates a comment in the format of the client's API version
+func (c *Client) NewComment(text string) Comment {
+	if c.APIVersion == "3" {
+		return Comment{Text: text, Document: adf.FromText(text)}
+	}
+	return Comment{Text: text}
+}
+
+// NewRequest creates an authorized request with body encoded as JSON
+func (c *Client) NewRequest(method, url string, body any) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(jsonBody)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return nil, err
+	}
+	if c.Credentials != nil {
+		authorization, err := c.Credentials.AuthorizationHeader()
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Add("Authorization", authorization)
+	}
+	req.Header.Add("Accept", "application/json")
+	if body != nil {
+		req.Header.Add("Content-Type", "application/json")
+	}
+	// headers are not logged, as they carry credentials
+	log.Printf("[Prepared HTTP Request] %v %v\n", req.Method, req.URL)
+	return req, nil
+}
+
+// Do sends the request with retries and decodes a successful response into v.
+// Unsuccessful responses are returned as *ErrorCollection error.
+func (c *Client) Do(req *http.Request, v any) (*Response, error) {
+	res, attempts, err := rest.DoWithRetry(c.HTTP, req, c.Retry)
+	resp := &Response{Attempts: attempts}
+	if err != nil {
+		log.Printf("Failed to send %v %v after %v attempt(s): %v\n", req.Method, req.URL, attempts, err)
+		return resp, err
+	}
+	defer res.Body.Close()
+	resp.StatusCode = res.StatusCode
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return resp, fmt.Errorf("failed to read response body: %w", err)
+	}
+	log.Printf("Jira server responded: %v\n{%q}\n", res.Status, body)
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		errs := &ErrorCollection{StatusCode: res.StatusCode}
+		if len(body) > 0 {
+			if err := json.Unmarshal(body, errs); err != nil {
+				log.Println("Error unmarshalling error response:", err)
+			}
+		}
+		return resp, errs
+	}
+	if v != nil && len(body) > 0 {
+		if err := json.Unmarshal(body, v); err != nil {
+			return resp, fmt.Errorf("error unmarshalling response: %w", err)
+		}
+	}
+	return resp, nil
+}
+
+// AddWorklog creates a worklog in the issue, query holds optional parameters, e.g. adjustEstimate
+func (c *Client) AddWorklog(issue string, w WorklogCreate, query url.Values) (*Worklog, *Response, error) {
+	worklogURL := c.WorklogURL(issue)
+	if len(query) > 0 {
+		worklogURL += "?" + query.Encode()
+	}
+	req, err := c.NewRequest(http.MethodPost, worklogURL, w)
+	if err != nil {
+		return nil, &Response{}, err
+	}
+	worklog := &Worklog{}
+	resp, err := c.Do(req, worklog)
+	if err != nil {
+		return nil, resp, err
+	}
+	return worklog, resp, nil
+}
+
+// get sends GET request and decodes the response into v
+func (c *Client) get(url string, v any) error {
+	req, err := c.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.Do(req, v)
+	return err
+}
+
+// Myself returns the user the client is authenticated as
+func (c *Client) Myself() (*User, error) {
+	user := &User{}
+	if err := c.get(c.URL("myself"), user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// SearchIssues returns all issues matching the JQL query, reading all result pages
+func (c *Client) SearchIssues(jql string, fields ...string) ([]Issue, error) {
+	var issues []Issue
+	for {
+		query := url.Values{}
+		query.Set("jql", jql)
+		query.Set("startAt", strconv.Itoa(len(issues)))
+		query.Set("maxResults", strconv.Itoa(pageSize))
+		if len(fields) > 0 {
+			query.Set("fields", strings.Join(fields, ","))
+		}
+		page := &searchResult{}
+		if err := c.get(c.URL("search")+"?"+query.Encode(), page); err != nil {
+			return nil, err
+		}
+		issues = append(issues, page.Issues...)
+		if len(page.Issues) == 0 || len(issues) >= page.Total {
+			return issues, nil
+		}
+	}
+}
+
+// IssueWorklogs returns all worklogs of the issue, reading all result pages
+func (c *Client) IssueWorklogs(issue string) ([]Worklog, error) {
+	var worklogs []Worklog
+	for {
+		query := url.Values{}
+		query.Set("startAt", strconv.Itoa(len(worklogs)))
+		query.Set("maxResults", strconv.Itoa(pageSize))
+		page := &worklogsPage{}
+		if err := c.get(c.WorklogURL(issue)+"?"+query.Encode(), page); err != nil {
+			return nil, err
+		}
+		for _, w := range page.Worklogs {
+			w.Issue = issue
+			worklogs = append(worklogs, w)
+		}
+		if len(page.Worklogs) == 0 || len(worklogs) >= page.Total {
+			return worklogs, nil
+		}
+	}
+}
+
+// UserWorklogs returns worklogs of the user, started in [from, to] days range
+func (c *Client) UserWorklogs(user *User, from, to time.Time) ([]Worklog, error) {
+	const jqlDate = "2006-01-02"
+	jql := fmt.Sprintf(`worklogAuthor = currentUser() AND worklogDate >= "%v" AND worklogDate <= "%v" ORDER BY key`,
+		from.Format(jqlDate), to.Format(jqlDate))
+	issues, err := c.SearchIssues(jql, "key")
+	if err != nil {
+		return nil, err
+	}
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, to.Location()).AddDate(0, 0, 1)
+	var userWorklogs []Worklog
+	for _, issue := range issues {
+		worklogs, err := c.IssueWorklogs(issue.Key)
+		if err != nil {
+			return nil, err
+		}
+		for _, w := range worklogs {
+			started, err := ParseTime(w.Started)
+			if err != nil {
+				log.Printf("Skipping worklog %v with unexpected started %q: %v\n", w.ID, w.Started, err)
+				continue
+			}
+			if user.Is(w.Author) && !started.Before(start) && started.Before(end) {
+				userWorklogs = append(userWorklogs, w)
+			}
+		}
+	}
+	return userWorklogs, nil
+}
+
+// Issue returns the issue by key with the requested fields, all fields if none are requested
+func (c *Client) Issue(key string, fields ...string) (*Issue, error) {
+	issueURL := c.URL("issue/%v", key)
+	if len(fields) > 0 {
+		query := url.Values{}
+		query.Set("fields", strings.Join(fields, ","))
+		issueURL += "?" + query.Encode()
+	}
+	issue := &Issue{}
+	if err := c.get(issueURL, issue); err != nil {
+		return nil, err
+	}
+	return issue, nil
+}
+
+// MyPermissions returns whether the current user has the permissions in the issue, e.g. PermissionWorkOnIssues
+func (c *Client) MyPermissions(issueKey string, permissions ...string) (map[string]bool, error) {
+	query := url.Values{}
+	query.Set("issueKey", issueKey)
+	query.Set("permissions", strings.Join(permissions, ","))
+	result := &permissionsResult{}
+	if err := c.get(c.URL("mypermissions")+"?"+query.Encode(), result); err != nil {
+		return nil, err
+	}
+	granted := map[string]bool{}
+	for _, p := range permissions {
+		granted[p] = result.Permissions[p].HavePermission
+	}
+	return granted, nil
+}
+
+// Worklog returns the issue's worklog by ID
+func (c *Client) Worklog(issue, id string) (*Worklog, error) {
+	worklog := &Worklog{}
+	if err := c.get(c.URL("issue/%v/worklog/%v", issue, id), worklog); err != nil {
+		return nil, err
+	}
+	worklog.Issue = issue
+	return worklog, nil
+}
+
+// UpdateWorklog updates the issue's worklog by ID
+func (c *Client) UpdateWorklog(issue, id string, w WorklogCreate) (*Worklog, *Response, error) {
+	req, err := c.NewRequest(http.MethodPut, c.URL("issue/%v/worklog/%v", issue, id), w)
+	if err != nil {
+		return nil, &Response{}, err
+	}
+	worklog := &Worklog{}
+	resp, err := c.Do(req, worklog)
+	if err != nil {
+		return nil, resp, err
+	}
+	return worklog, resp, nil
+}
+
+// DeleteWorklog deletes the issue's worklog by ID
+func (c *Client) DeleteWorklog(issue, id string) (*Response, error) {
+	req, err := c.NewRequest(http.MethodDelete, c.URL("issue/%v/worklog/%v", issue, id), nil)
+	if err != nil {
+		return &Response{}, err
+	}
+	return c.Do(req, nil)
+}
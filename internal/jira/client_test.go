@@ -0,0 +1,158 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/philgal/jtl/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_NewRequestAuthorization(t *testing.T) {
+	tests := []struct {
+		name  string
+		cred  model.Credentials
+		want  string
+		isErr bool
+	}{
+		{"Should use basic auth by default", model.Credentials{Username: "user", Password: "pass"}, "Basic dXNlcjpwYXNz", false},
+		{"Should use bearer token", model.Credentials{Type: model.AuthBearer, Token: "pat-token"}, "Bearer pat-token", false},
+		{"Should treat pat as bearer", model.Credentials{Type: model.AuthPAT, Token: "pat-token"}, "Bearer pat-token", false},
+		{"Should use email and API token for cloud", model.Credentials{Type: model.AuthCloudToken, Username: "me@example.com", Token: "api"}, "Basic bWVAZXhhbXBsZS5jb206YXBp", false},
+		{"Should fail on unknown type", model.Credentials{Type: "oauth"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient("https://jira.example.com/", "", &tt.cred, nil)
+			req, err := client.NewRequest(http.MethodPost, client.WorklogURL("TICKET-1"), WorklogCreate{})
+			if tt.isErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, req.Header.Get("Authorization"))
+			assert.Equal(t, "https://jira.example.com/rest/api/2/issue/TICKET-1/worklog", req.URL.String())
+		})
+	}
+}
+
+func TestClient_NewRequestLogging(t *testing.T) {
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	t.Run("Should not log credentials", func(t *testing.T) {
+		client := NewClient("https://jira.example.com/", "", &model.Credentials{Type: model.AuthBearer, Token: "pat-token"}, nil)
+
+		_, err := client.NewRequest(http.MethodPost, client.WorklogURL("TICKET-1"), WorklogCreate{})
+
+		assert.NoError(t, err)
+		assert.Contains(t, logged.String(), "POST https://jira.example.com/rest/api/2/issue/TICKET-1/worklog")
+		assert.NotContains(t, logged.String(), "pat-token")
+	})
+}
+
+func TestClient_AddWorklog(t *testing.T) {
+	var gotBody []byte
+	var gotQuery string
+	status, response := http.StatusCreated, `{"id":"100028","issueId":"10002","comment":"Quote \" and \\ backslash","started":"2020-04-09T00:28:56.595+0000","timeSpent":"3h 20m"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotQuery = r.URL.RawQuery
+		w.WriteHeader(status)
+		io.WriteString(w, response)
+	}))
+	t.Cleanup(server.Close)
+	client := NewClient(server.URL, "2", &model.Credentials{Username: "u", Password: "p"}, server.Client())
+
+	t.Run("Should encode special characters in comment", func(t *testing.T) {
+		comment := "Some \"repeating\" meeting!\nC:\\path"
+
+		worklog, resp, err := client.AddWorklog("TICKET-1", WorklogCreate{TimeSpentSeconds: 3600, Comment: client.NewComment(comment), Started: "2020-04-15T11:30:00.000+0200"}, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 201, resp.StatusCode)
+		assert.Equal(t, 1, resp.Attempts)
+		assert.Equal(t, "100028", worklog.ID)
+		assert.Equal(t, "Quote \" and \\ backslash", worklog.Comment.Text)
+		sent := map[string]any{}
+		assert.NoError(t, json.Unmarshal(gotBody, &sent))
+		assert.Equal(t, comment, sent["comment"])
+		assert.Equal(t, float64(3600), sent["timeSpentSeconds"])
+	})
+
+	t.Run("Should send query parameters", func(t *testing.T) {
+		query := model.EstimateAdjustment{Adjust: model.EstimateNew, NewEstimate: "2d"}.Query()
+
+		_, _, err := client.AddWorklog("TICKET-1", WorklogCreate{}, query)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "adjustEstimate=new&newEstimate=2d", gotQuery)
+	})
+
+	t.Run("Should decode ADF comment of API v3 as text", func(t *testing.T) {
+		response = `{"id":"100029","comment":{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"from v3"}]}]}}`
+
+		worklog, _, err := client.AddWorklog("TICKET-1", WorklogCreate{}, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "from v3", worklog.Comment.Text)
+		assert.NotNil(t, worklog.Comment.Document)
+	})
+
+	t.Run("Should return error collection on failure", func(t *testing.T) {
+		status, response = http.StatusBadRequest, `{"errorMessages":["Issue does not exist"],"errors":{"timeLogged":"Required"}}`
+
+		_, resp, err := client.AddWorklog("TICKET-1", WorklogCreate{}, nil)
+
+		var errs *ErrorCollection
+		assert.ErrorAs(t, err, &errs)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "Issue does not exist; timeLogged: Required", err.Error())
+	})
+}
+
+func TestClient_CheckIssues(t *testing.T) {
+	responses := map[string]string{
+		"/rest/api/2/issue/OPEN-1":   `{"id":"1","key":"OPEN-1","fields":{"summary":"Open issue","status":{"name":"In Progress"}}}`,
+		"/rest/api/2/issue/DONE-1":   `{"id":"2","key":"DONE-1","fields":{"summary":"Done issue","status":{"name":"Done"}}}`,
+		"/rest/api/2/issue/LOCKED-1": `{"id":"3","key":"LOCKED-1","fields":{"summary":"Locked issue","status":{"name":"Open"}}}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/api/2/mypermissions" {
+			have := r.URL.Query().Get("issueKey") != "LOCKED-1"
+			json.NewEncoder(w).Encode(map[string]any{"permissions": map[string]any{PermissionWorkOnIssues: map[string]any{"havePermission": have}}})
+			return
+		}
+		body, found := responses[r.URL.Path]
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"errorMessages":["Issue does not exist or you do not have permission to see it."]}`)
+			return
+		}
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(server.Close)
+	client := NewClient(server.URL, "2", &model.Credentials{Username: "u", Password: "p"}, server.Client())
+
+	checks := client.CheckIssues([]string{"OPEN-1", "DONE-1", "TYPO-1", "LOCKED-1", "OPEN-1"}, []string{"closed", "done"})
+
+	t.Run("Should check each issue once", func(t *testing.T) {
+		assert.Len(t, checks, 4)
+	})
+	t.Run("Should accept open issue with permission", func(t *testing.T) {
+		assert.Equal(t, IssueCheck{Key: "OPEN-1", Summary: "Open issue", Status: "In Progress"}, checks[0])
+		assert.True(t, checks[0].IsValid())
+	})
+	t.Run("Should reject closed, missing and not permitted issues", func(t *testing.T) {
+		assert.Equal(t, "issue is Done", checks[1].Problem)
+		assert.Equal(t, "issue does not exist or is not visible", checks[2].Problem)
+		assert.Equal(t, "you may not log work in the issue", checks[3].Problem)
+	})
+}
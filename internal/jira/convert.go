@@ -0,0 +1,47 @@
+package jira
+
+import (
+	"time"
+
+	"github.com/philgal/jtl/internal/config"
+	"github.com/philgal/jtl/internal/csv"
+	"github.com/philgal/jtl/internal/duration"
+)
+
+// RecordFromWorklog converts a worklog into a data file record with the worklog's ID, so it is known as pushed
+func RecordFromWorklog(w Worklog) csv.Record {
+	started := w.Started
+	if t, err := ParseTime(w.Started); err == nil {
+		started = t.In(time.Local).Format(config.DefaultDateTimePattern)
+	}
+	return csv.Record{
+		ID:         w.ID,
+		StartedTs:  started,
+		Comment:    w.Comment.Text,
+		TimeSpent:  duration.ToString(w.minutesSpent()),
+		Ticket:     w.Issue,
+		Visibility: w.Visibility.String(),
+	}
+}
+
+func (w Worklog) minutesSpent() int {
+	if w.TimeSpentSeconds > 0 {
+		return w.TimeSpentSeconds / 60
+	}
+	return duration.ToMinutes(w.TimeSpent)
+}
+
+// FindWorklog returns the first worklog of the author, started at the same minute and with the same duration,
+// skipping worklogs with excluded IDs. Returns nil if there is no such worklog.
+func FindWorklog(worklogs []Worklog, author *User, started time.Time, minutes int, exclude func(id string) bool) *Worklog {
+	for i, w := range worklogs {
+		if !author.Is(w.Author) || w.minutesSpent() != minutes || exclude(w.ID) {
+			continue
+		}
+		wStarted, err := ParseTime(w.Started)
+		if err == nil && wStarted.Truncate(time.Minute).Equal(started.Truncate(time.Minute)) {
+			return &worklogs[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package jira
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindWorklog(t *testing.T) {
+	me := &User{Name: "me", Key: "me"}
+	started := time.Date(2020, 4, 15, 11, 30, 0, 0, time.UTC)
+	worklogs := []Worklog{
+		{ID: "1", Author: &User{Name: "teammate", Key: "teammate"}, Started: "2020-04-15T11:30:00.000+0000", TimeSpentSeconds: 600},
+		{ID: "2", Author: me, Started: "2020-04-15T11:30:00.000+0000", TimeSpentSeconds: 1200},
+		{ID: "3", Author: me, Started: "2020-04-15T13:30:41.000+0200", TimeSpentSeconds: 600},
+		{ID: "4", Author: me, Started: "2020-04-15T11:30:00.000+0000", TimeSpentSeconds: 600},
+	}
+	none := func(string) bool { return false }
+
+	t.Run("Should find worklog of the author with the same started minute and duration", func(t *testing.T) {
+		found := FindWorklog(worklogs, me, started, 10, none)
+
+		assert.Equal(t, "3", found.ID)
+	})
+
+	t.Run("Should skip excluded worklogs", func(t *testing.T) {
+		found := FindWorklog(worklogs, me, started, 10, func(id string) bool { return id == "3" })
+
+		assert.Equal(t, "4", found.ID)
+	})
+
+	t.Run("Should return nil if nothing matches", func(t *testing.T) {
+		assert.Nil(t, FindWorklog(worklogs, me, started, 30, none))
+	})
+}
@@ -0,0 +1,56 @@
+package jira
+
+import (
+	"sync"
+	"time"
+)
+
+// WorklogFinder looks for worklogs of a user, which already exist in Jira.
+// Worklogs are fetched once per issue, and each found worklog is claimed, so it's never returned twice.
+// It is safe for concurrent use.
+type WorklogFinder struct {
+	client  *Client
+	author  *User
+	mu      sync.Mutex
+	issues  map[string]*issueWorklogs
+	claimed map[string]bool
+}
+
+type issueWorklogs struct {
+	once     sync.Once
+	worklogs []Worklog
+	err      error
+}
+
+// NewWorklogFinder creates a finder of the author's worklogs. Worklogs with known IDs, e.g. already in the data file, are never returned.
+func NewWorklogFinder(client *Client, author *User, knownIDs ...string) *WorklogFinder {
+	f := &WorklogFinder{client: client, author: author, issues: map[string]*issueWorklogs{}, claimed: map[string]bool{}}
+	for _, id := range knownIDs {
+		f.claimed[id] = true
+	}
+	return f
+}
+
+// Find returns the author's worklog of the issue, started at the same minute and with the same duration, nil if there is none
+func (f *WorklogFinder) Find(issue string, started time.Time, minutes int) (*Worklog, error) {
+	f.mu.Lock()
+	iw, found := f.issues[issue]
+	if !found {
+		iw = &issueWorklogs{}
+		f.issues[issue] = iw
+	}
+	f.mu.Unlock()
+
+	iw.once.Do(func() { iw.worklogs, iw.err = f.client.IssueWorklogs(issue) })
+	if iw.err != nil {
+		return nil, iw.err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w := FindWorklog(iw.worklogs, f.author, started, minutes, func(id string) bool { return f.claimed[id] })
+	if w != nil {
+		f.claimed[w.ID] = true
+	}
+	return w, nil
+}
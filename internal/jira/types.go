@@ -0,0 +1,246 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/philgal/jtl/internal/adf"
+	"github.com/philgal/jtl/internal/config"
+)
+
+// WorklogCreate is a request body of a new worklog.
+// Time spent is sent in seconds, as Jira converts days of a duration like 1d by its own day length, not by schedule.day.
+type WorklogCreate struct {
+	TimeSpentSeconds int         `json:"timeSpentSeconds"`
+	Comment          Comment     `json:"comment"`
+	Started          string      `json:"started"`
+	Visibility       *Visibility `json:"visibility,omitempty"`
+}
+
+// Visibility restricts a worklog to members of a group or a role
+type Visibility struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// Types of worklog visibility
+const (
+	VisibilityGroup = "group"
+	VisibilityRole  = "role"
+)
+
+// ParseVisibility parses visibility of a data file record, e.g. "role:Developers" or "group:jira-users".
+// Empty string means no restriction, returned as nil.
+func ParseVisibility(s string) (*Visibility, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	visibilityType, value, _ := strings.Cut(s, ":")
+	v := &Visibility{Type: strings.ToLower(strings.TrimSpace(visibilityType)), Value: strings.TrimSpace(value)}
+	if (v.Type != VisibilityGroup && v.Type != VisibilityRole) || v.Value == "" {
+		return nil, fmt.Errorf("invalid visibility %q, expected %v:<name> or %v:<name>", s, VisibilityRole, VisibilityGroup)
+	}
+	return v, nil
+}
+
+// String returns visibility the way it's kept in the data file, empty for nil
+func (v *Visibility) String() string {
+	if v == nil {
+		return ""
+	}
+	return v.Type + ":" + v.Value
+}
+
+// Worklog is a worklog as returned by Jira
+type Worklog struct {
+	Self             string      `json:"self,omitempty"`
+	ID               string      `json:"id"`
+	IssueID          string      `json:"issueId"`
+	Author           *User       `json:"author,omitempty"`
+	Comment          Comment     `json:"comment"`
+	Started          string      `json:"started"`
+	TimeSpent        string      `json:"timeSpent"`
+	TimeSpentSeconds int         `json:"timeSpentSeconds"`
+	Visibility       *Visibility `json:"visibility,omitempty"`
+	// Issue is a key of the worklog's issue, it's not a part of Jira response and is set by the client
+	Issue string `json:"-"`
+}
+
+// User is a Jira user. Jira Server identifies users by name and key, Jira Cloud by accountId.
+type User struct {
+	Name         string `json:"name,omitempty"`
+	Key          string `json:"key,omitempty"`
+	AccountID    string `json:"accountId,omitempty"`
+	EmailAddress string `json:"emailAddress,omitempty"`
+	DisplayName  string `json:"displayName,omitempty"`
+}
+
+// Is returns true if both are the same user
+func (u *User) Is(other *User) bool {
+	if u == nil || other == nil {
+		return false
+	}
+	if u.AccountID != "" || other.AccountID != "" {
+		return u.AccountID == other.AccountID
+	}
+	if u.Key != "" && other.Key != "" {
+		return u.Key == other.Key
+	}
+	return u.Name != "" && u.Name == other.Name
+}
+
+// ErrorCollection is an error body returned by Jira for unsuccessful requests
+type ErrorCollection struct {
+	StatusCode    int               `json:"-"`
+	ErrorMessages []string          `json:"errorMessages"`
+	Errors        map[string]string `json:"errors"`
+}
+
+func (e *ErrorCollection) Error() string {
+	reasons := slices.Clone(e.ErrorMessages)
+	for _, field := range slices.Sorted(maps.Keys(e.Errors)) {
+		reasons = append(reasons, fmt.Sprintf("%v: %v", field, e.Errors[field]))
+	}
+	if len(reasons) == 0 {
+		return fmt.Sprintf("jira server responded %v", e.StatusCode)
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// Comment is a worklog comment: a plain string in REST API v2 and an Atlassian Document Format document in v3.
+// Text is always set when a comment is decoded, Document only for v3.
+type Comment struct {
+	Text     string
+	Document *adf.Node
+}
+
+// MarshalJSON writes the document if it is set, text otherwise
+func (c Comment) MarshalJSON() ([]byte, error) {
+	if c.Document != nil {
+		return json.Marshal(c.Document)
+	}
+	return json.Marshal(c.Text)
+}
+
+// UnmarshalJSON reads either a plain string or a document, converting it to text
+func (c *Comment) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		*c = Comment{}
+		return nil
+	}
+	if data[0] == '"' {
+		c.Document = nil
+		return json.Unmarshal(data, &c.Text)
+	}
+	doc := &adf.Node{}
+	if err := json.Unmarshal(data, doc); err != nil {
+		return err
+	}
+	c.Document, c.Text = doc, adf.ToText(doc)
+	return nil
+}
+
+// FormatTime formats time the way Jira expects worklog started timestamps
+func FormatTime(t time.Time) string {
+	return t.Format(config.JiraDateTimePattern)
+}
+
+// ParseTime parses Jira timestamps, e.g. worklog started
+func ParseTime(ts string) (time.Time, error) {
+	return time.Parse(config.JiraDateTimePattern, ts)
+}
+
+// Issue is a Jira issue with the fields jtl needs
+type Issue struct {
+	ID     string      `json:"id"`
+	Key    string      `json:"key"`
+	Fields IssueFields `json:"fields"`
+}
+
+// IssueFields are the requested fields of an issue. Custom holds all fields as they are, e.g. custom fields.
+type IssueFields struct {
+	Summary    string                     `json:"summary,omitempty"`
+	Status     *Status                    `json:"status,omitempty"`
+	Project    *Project                   `json:"project,omitempty"`
+	IssueType  *IssueType                 `json:"issuetype,omitempty"`
+	Parent     *Issue                     `json:"parent,omitempty"`
+	Components []Component                `json:"components,omitempty"`
+	Custom     map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON reads known fields and keeps all of them in Custom
+func (f *IssueFields) UnmarshalJSON(data []byte) error {
+	type fields IssueFields
+	if err := json.Unmarshal(data, (*fields)(f)); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &f.Custom)
+}
+
+// String returns a value of a string field, e.g. Epic Link custom field, empty if it's not a string
+func (f IssueFields) String(field string) string {
+	var value string
+	if raw, found := f.Custom[field]; found && json.Unmarshal(raw, &value) == nil {
+		return value
+	}
+	return ""
+}
+
+// Project is a Jira project
+type Project struct {
+	Key  string `json:"key"`
+	Name string `json:"name,omitempty"`
+}
+
+// IssueType is a type of an issue, e.g. Story or Epic
+type IssueType struct {
+	Name string `json:"name"`
+}
+
+// Component is a project component
+type Component struct {
+	Name string `json:"name"`
+}
+
+// Status is a workflow status of an issue
+type Status struct {
+	Name string `json:"name"`
+}
+
+// PermissionWorkOnIssues is a permission to log work in an issue
+const PermissionWorkOnIssues = "WORK_ON_ISSUES"
+
+type permissionsResult struct {
+	Permissions map[string]struct {
+		HavePermission bool `json:"havePermission"`
+	} `json:"permissions"`
+}
+
+type searchResult struct {
+	StartAt    int     `json:"startAt"`
+	MaxResults int     `json:"maxResults"`
+	Total      int     `json:"total"`
+	Issues     []Issue `json:"issues"`
+}
+
+type worklogsPage struct {
+	StartAt    int       `json:"startAt"`
+	MaxResults int       `json:"maxResults"`
+	Total      int       `json:"total"`
+	Worklogs   []Worklog `json:"worklogs"`
+}
+
+// IsNotFound returns true if err is Jira's response for a missing (or not visible) resource
+func IsNotFound(err error) bool {
+	var errs *ErrorCollection
+	return errors.As(err, &errs) && errs.StatusCode == http.StatusNotFound
+}
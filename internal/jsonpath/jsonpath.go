@@ -0,0 +1,112 @@
+package jsonpath
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Select returns a value of a JSON document by a JSONPath-like selector, e.g. "$.data.items[0].id".
+// Supported are dot-separated object keys and array indexes, the leading "$" is optional.
+func Select(doc []byte, selector string) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(doc))
+	// numbers are kept as they are, so big IDs don't lose precision
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	steps, err := parse(selector)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range steps {
+		switch node := value.(type) {
+		case map[string]any:
+			if s.isIndex {
+				return nil, fmt.Errorf("%v: expected array, got object", selector)
+			}
+			v, found := node[s.key]
+			if !found {
+				return nil, fmt.Errorf("%v: key %q not found", selector, s.key)
+			}
+			value = v
+		case []any:
+			if !s.isIndex {
+				return nil, fmt.Errorf("%v: expected object, got array", selector)
+			}
+			if s.index < 0 || s.index >= len(node) {
+				return nil, fmt.Errorf("%v: index %v out of range", selector, s.index)
+			}
+			value = node[s.index]
+		default:
+			return nil, fmt.Errorf("%v: cannot select %v from a scalar value", selector, s)
+		}
+	}
+	return value, nil
+}
+
+// SelectString returns a scalar value of a JSON document by selector as string
+func SelectString(doc []byte, selector string) (string, error) {
+	value, err := Select(doc, selector)
+	if err != nil {
+		return "", err
+	}
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case nil:
+		return "", fmt.Errorf("%v: value is null", selector)
+	default:
+		return "", fmt.Errorf("%v: expected a scalar value, got %T", selector, value)
+	}
+}
+
+type step struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+func (s step) String() string {
+	if s.isIndex {
+		return fmt.Sprintf("[%v]", s.index)
+	}
+	return s.key
+}
+
+func parse(selector string) ([]step, error) {
+	rest := strings.TrimPrefix(strings.TrimSpace(selector), "$")
+	var steps []step
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("%v: unclosed [", selector)
+			}
+			index, err := strconv.Atoi(strings.TrimSpace(rest[1:end]))
+			if err != nil {
+				return nil, fmt.Errorf("%v: invalid index %q", selector, rest[1:end])
+			}
+			steps = append(steps, step{index: index, isIndex: true})
+			rest = rest[end+1:]
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			steps = append(steps, step{key: rest[:end]})
+			rest = rest[end:]
+		}
+	}
+	return steps, nil
+}
@@ -0,0 +1,33 @@
+package jsonpath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectString(t *testing.T) {
+	doc := []byte(`{"id":9007199254740993,"data":{"items":[{"id":"a-1"},{"id":"a-2","ok":true}]},"empty":null}`)
+	tests := []struct {
+		selector string
+		want     string
+		isErr    bool
+	}{
+		{"$.id", "9007199254740993", false},
+		{"id", "9007199254740993", false},
+		{"$.data.items[1].id", "a-2", false},
+		{"data.items[1].ok", "true", false},
+		{"$.data.items[2].id", "", true},
+		{"$.data.missing", "", true},
+		{"$.data", "", true},
+		{"$.empty", "", true},
+		{"$.data.items.id", "", true},
+	}
+	for _, tt := range tests {
+		t.Run("Should select "+tt.selector, func(t *testing.T) {
+			got, err := SelectString(doc, tt.selector)
+			assert.Equal(t, tt.isErr, err != nil, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
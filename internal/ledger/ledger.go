@@ -0,0 +1,131 @@
+package ledger
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/philgal/jtl/internal/csv"
+)
+
+// Entry is a pushed record as it was when pushed
+type Entry struct {
+	ID     string `json:"id"`
+	Ticket string `json:"ticket"`
+	Hash   string `json:"hash"`
+}
+
+// Ledger keeps a content hash of every pushed record of a data file in a sidecar file,
+// so that records edited or removed after the push can be synced to Jira.
+type Ledger struct {
+	Path    string           `json:"-"`
+	Entries map[string]Entry `json:"entries"`
+}
+
+// PathFor returns a path of the ledger of the data file, e.g. Apr-2020.csv.ledger.json
+func PathFor(dataFile string) string {
+	return dataFile + ".ledger.json"
+}
+
+// PathForTarget returns a path of the ledger of records mirrored to a named target, e.g. Apr-2020.csv.billing.ledger.json
+func PathForTarget(dataFile, target string) string {
+	return dataFile + "." + target + ".ledger.json"
+}
+
+// Load reads the ledger from disk. A missing file is an empty ledger.
+func Load(path string) (*Ledger, error) {
+	l := &Ledger{Path: path, Entries: map[string]Entry{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, err
+	}
+	if l.Entries == nil {
+		l.Entries = map[string]Entry{}
+	}
+	return l, nil
+}
+
+// Save writes the ledger to disk
+func (l *Ledger) Save() error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	log.Printf("Saving ledger with %v entries to %v\n", len(l.Entries), l.Path)
+	return os.WriteFile(l.Path, data, 0644)
+}
+
+// Track remembers the current content of a pushed record
+func (l *Ledger) Track(rec csv.Record) {
+	if rec.IsPushed() {
+		l.Entries[rec.ID] = Entry{ID: rec.ID, Ticket: rec.Ticket, Hash: rec.Hash()}
+	}
+}
+
+// TrackMirror remembers the current content of a pushed record, mirrored to another target as a worklog with mirrorID.
+// Entries of mirrored records are keyed by the records' IDs.
+func (l *Ledger) TrackMirror(rec csv.Record, mirrorID string) {
+	if rec.IsPushed() {
+		l.Entries[rec.ID] = Entry{ID: mirrorID, Ticket: rec.Ticket, Hash: rec.Hash()}
+	}
+}
+
+// ForgetMirror removes the entry of the mirrored worklog
+func (l *Ledger) ForgetMirror(mirrorID string) {
+	for key, entry := range l.Entries {
+		if entry.ID == mirrorID {
+			delete(l.Entries, key)
+		}
+	}
+}
+
+// Forget removes the worklog from the ledger, e.g. after it's deleted in Jira
+func (l *Ledger) Forget(id string) {
+	delete(l.Entries, id)
+}
+
+// IsTracked returns true if the pushed record is in the ledger
+func (l *Ledger) IsTracked(rec csv.Record) bool {
+	_, found := l.Entries[rec.ID]
+	return found
+}
+
+// IsModified returns true if the pushed record has been changed since it was tracked
+func (l *Ledger) IsModified(rec csv.Record) bool {
+	entry, found := l.Entries[rec.ID]
+	return rec.IsPushed() && found && entry.Hash != rec.Hash()
+}
+
+// Removed returns entries, which records are no longer in the data file, i.e. marked as removed
+func (l *Ledger) Removed(recs []csv.Record) []Entry {
+	var removed []Entry
+	for _, id := range l.RemovedIDs(recs) {
+		removed = append(removed, l.Entries[id])
+	}
+	return removed
+}
+
+// RemovedIDs returns sorted IDs of records, which are no longer in the data file.
+// Entries are keyed by records' IDs, so for a mirror ledger they differ from the entries' own IDs.
+func (l *Ledger) RemovedIDs(recs []csv.Record) []string {
+	present := map[string]bool{}
+	for _, r := range recs {
+		present[r.ID] = true
+	}
+	var removed []string
+	for _, id := range slices.Sorted(maps.Keys(l.Entries)) {
+		if !present[id] {
+			removed = append(removed, id)
+		}
+	}
+	return removed
+}
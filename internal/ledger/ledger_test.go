@@ -0,0 +1,56 @@
+package ledger
+
+import (
+	"path"
+	"testing"
+
+	"github.com/philgal/jtl/internal/csv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLedger(t *testing.T) {
+	l, err := Load(PathFor(path.Join(t.TempDir(), "Apr-2020.csv")))
+	assert.NoError(t, err)
+	pushed := csv.Record{ID: "1", StartedTs: "14 Apr 2020 11:30", Comment: "comment", TimeSpent: "90m", Ticket: "TICKET-1"}
+	removed := csv.Record{ID: "2", StartedTs: "14 Apr 2020 13:00", Comment: "comment", TimeSpent: "1h", Ticket: "TICKET-2"}
+	l.Track(pushed)
+	l.Track(removed)
+	l.Track(csv.Record{Ticket: "TICKET-3"}) // not pushed, ignored
+
+	t.Run("Should not treat equal durations as modification", func(t *testing.T) {
+		reformatted := pushed
+		reformatted.TimeSpent = "1h 30m"
+
+		assert.False(t, l.IsModified(reformatted))
+	})
+
+	t.Run("Should detect modified record", func(t *testing.T) {
+		edited := pushed
+		edited.Comment = "fixed typo"
+
+		assert.True(t, l.IsModified(edited))
+	})
+
+	t.Run("Should detect removed records", func(t *testing.T) {
+		assert.Equal(t, []Entry{{ID: "2", Ticket: "TICKET-2", Hash: removed.Hash()}}, l.Removed([]csv.Record{pushed}))
+	})
+
+	t.Run("Should return IDs of removed records of mirror ledger", func(t *testing.T) {
+		mirror := &Ledger{Entries: map[string]Entry{}}
+		mirror.TrackMirror(pushed, "B-1")
+		mirror.TrackMirror(removed, "B-2")
+
+		assert.Equal(t, []string{"2"}, mirror.RemovedIDs([]csv.Record{pushed}))
+		assert.Equal(t, "B-2", mirror.Removed([]csv.Record{pushed})[0].ID)
+	})
+
+	t.Run("Should save and load entries", func(t *testing.T) {
+		assert.NoError(t, l.Save())
+
+		loaded, err := Load(l.Path)
+
+		assert.NoError(t, err)
+		assert.Equal(t, l.Entries, loaded.Entries)
+		assert.Len(t, loaded.Entries, 2)
+	})
+}
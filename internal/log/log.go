@@ -21,6 +21,8 @@ type ExecutorArgs struct {
 	TimeSpent string
 	Comment   string
 	StartedTs string
+	// Visibility restricts who can see the worklog, e.g. "role:Developers"
+	Visibility string
 }
 
 type AutoFitting struct {
@@ -38,11 +40,12 @@ func (e Normal) Execute() {
 		os.Exit(1)
 	}
 	file.AddRecord(csv.Record{
-		ID:        "",
-		StartedTs: e.StartedTs,
-		Comment:   e.Comment,
-		TimeSpent: e.TimeSpent,
-		Ticket:    e.Ticket,
+		ID:         "",
+		StartedTs:  e.StartedTs,
+		Comment:    e.Comment,
+		TimeSpent:  e.TimeSpent,
+		Ticket:     e.Ticket,
+		Visibility: e.Visibility,
 	})
 	file.Write()
 }
@@ -56,13 +59,19 @@ func (e AutoFitting) Execute() {
 	})
 
 	logDate, _ := time.Parse(config.DefaultDateTimePattern, e.StartedTs)
+	// maximum daily duration is the target of the day in the work schedule
+	dailyMinutes := int(config.WorkSchedule().Target(logDate) / time.Minute)
+	if dailyMinutes <= 0 {
+		fmt.Printf("%v is not a working day in the schedule, nothing to fit to, logging %v as is\n", logDate.Format(config.DefaultDatePattern), e.TimeSpent)
+		Normal{ExecutorArgs: e.ExecutorArgs}.Execute()
+		return
+	}
 	//if dates are equal, count hours
 	sameDateRecs := file.Filter(csv.SameDateRecordsFilter(logDate))
 	minutesSpentToDate := timeSpentToDateInMin(sameDateRecs, logDate)
 
 	// for example 500 > 480 -> 20m to log
-	// todo: make max daily duration configurable
-	if duration.ToMinutes(e.TimeSpent)+minutesSpentToDate >= duration.EightHoursInMin {
+	if duration.ToMinutes(e.TimeSpent)+minutesSpentToDate >= dailyMinutes {
 		// todo: make this logic optional if some "distribute" flag is set
 		adjustableRecords := csv.Filter(sameDateRecs, func(r csv.Record) bool { return r.ID == "" })
 		if len(adjustableRecords) == 0 {
@@ -71,7 +80,7 @@ func (e AutoFitting) Execute() {
 		}
 
 		// calc timeSpent for each record
-		totalTimeSpentToLog := math.Min(float64(minutesSpentToDate+duration.ToMinutes(e.TimeSpent)), duration.EightHoursInMin)
+		totalTimeSpentToLog := math.Min(float64(minutesSpentToDate+duration.ToMinutes(e.TimeSpent)), float64(dailyMinutes))
 		totalRecordsToLog := len(sameDateRecs) + 1
 		timeSpentPerRec := int(totalTimeSpentToLog / float64(totalRecordsToLog))
 		e.TimeSpent = duration.ToString(timeSpentPerRec)
@@ -90,13 +99,13 @@ func (e AutoFitting) Execute() {
 		}
 	} else {
 		// we have some time to log: do dynamic timeSpent & startedTs calculation for all today's records
-		// if today's records are empty, fill 8h with multiple records of 4h.
+		// if today's records are empty, fill the day with multiple records of a half of the day, e.g. 4h of 8h.
 		// if today's records are not empty, calculate timeSpent and startedTs based on the existing records
-		timeSpentMin := int(math.Min(float64(duration.EightHoursInMin-minutesSpentToDate), duration.EightHoursInMin/2))
+		timeSpentMin := int(math.Min(float64(dailyMinutes-minutesSpentToDate), float64(dailyMinutes/2)))
 		e.TimeSpent = duration.ToString(timeSpentMin)
 		fmt.Printf("Time spent will is trimmed to %s, to not to exceed %s\n",
 			e.TimeSpent,
-			duration.ToString(duration.EightHoursInMin))
+			duration.ToString(dailyMinutes))
 	}
 
 	// adjust startedTs to the last record: new startedTs = last rec.StartedTs + calculated time spent
@@ -109,11 +118,12 @@ func (e AutoFitting) Execute() {
 
 	if e.TimeSpent != "0m" {
 		file.AddRecord(csv.Record{
-			ID:        "",
-			StartedTs: e.StartedTs,
-			Comment:   e.Comment,
-			TimeSpent: e.TimeSpent,
-			Ticket:    e.Ticket,
+			ID:         "",
+			StartedTs:  e.StartedTs,
+			Comment:    e.Comment,
+			TimeSpent:  e.TimeSpent,
+			Ticket:     e.Ticket,
+			Visibility: e.Visibility,
 		})
 		file.Write()
 	} else {
@@ -0,0 +1,112 @@
+package model
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/philgal/jtl/internal/csv"
+	"github.com/spf13/viper"
+)
+
+// Alias is a short name of a ticket, optionally with defaults of its records
+type Alias struct {
+	Name       string `yaml:"-"`
+	Ticket     string `yaml:"ticket"`
+	Comment    string `yaml:"comment,omitempty"`
+	TimeSpent  string `yaml:"time,omitempty"`
+	Visibility string `yaml:"visibility,omitempty"`
+	// Estimate is an estimate adjustment of the ticket's worklogs, e.g. leave
+	Estimate string `yaml:"estimate,omitempty"`
+}
+
+// HasDefaults returns true if the alias sets defaults of its records
+func (a Alias) HasDefaults() bool {
+	return a.Comment != "" || a.TimeSpent != "" || a.Visibility != "" || a.Estimate != ""
+}
+
+// Aliases reads ticket aliases from config. An alias is either a ticket or a map with the ticket and defaults.
+// Alias names are case-insensitive, as config keys are.
+//
+//	alias:
+//	  jt1: JIRATICKET-1
+//	  standup:
+//	    ticket: MEET-7
+//	    comment: Daily standup
+//	    time: 15m
+//	    visibility: role:Developers
+//	    estimate: leave
+func Aliases() (map[string]Alias, error) {
+	aliases := map[string]Alias{}
+	for name, v := range viper.GetStringMap("alias") {
+		a := Alias{Name: name}
+		switch value := v.(type) {
+		case string:
+			a.Ticket = value
+		case map[string]any:
+			a.Ticket = stringOf(value, "ticket")
+			a.Comment = stringOf(value, "comment")
+			a.TimeSpent = stringOf(value, "time")
+			a.Visibility = stringOf(value, "visibility")
+			a.Estimate = stringOf(value, "estimate")
+		default:
+			return nil, fmt.Errorf("alias.%v: unsupported value %v", name, v)
+		}
+		if strings.TrimSpace(a.Ticket) == "" {
+			return nil, fmt.Errorf("alias.%v: ticket is not set", name)
+		}
+		a.Ticket = strings.TrimSpace(a.Ticket)
+		aliases[name] = a
+	}
+	return aliases, nil
+}
+
+// AliasNames returns sorted names of configured aliases
+func AliasNames(aliases map[string]Alias) []string {
+	return slices.Sorted(maps.Keys(aliases))
+}
+
+// ResolveAlias returns the alias with the name, if it's configured
+func ResolveAlias(name string) (Alias, bool) {
+	aliases, err := Aliases()
+	if err != nil {
+		return Alias{}, false
+	}
+	a, found := aliases[strings.ToLower(strings.TrimSpace(name))]
+	return a, found
+}
+
+// ResolveTicket returns the ticket of the alias, or the value itself if it's not an alias
+func ResolveTicket(ticketOrAlias string) string {
+	if a, found := ResolveAlias(ticketOrAlias); found {
+		return a.Ticket
+	}
+	return ticketOrAlias
+}
+
+// ResolveTickets resolves aliases of the tickets
+func ResolveTickets(ticketsOrAliases []string) []string {
+	tickets := make([]string, len(ticketsOrAliases))
+	for i, t := range ticketsOrAliases {
+		tickets[i] = ResolveTicket(t)
+	}
+	return tickets
+}
+
+// ResolveRecordAliases replaces aliases with their tickets in records, which are not pushed yet, e.g. written into the data file by hand.
+// Returns the number of replaced aliases.
+func ResolveRecordAliases(recs []csv.Record) int {
+	aliases, err := Aliases()
+	if err != nil || len(aliases) == 0 {
+		return 0
+	}
+	resolved := 0
+	for i := range recs {
+		if a, found := aliases[strings.ToLower(recs[i].Ticket)]; found && !recs[i].IsPushed() {
+			recs[i].Ticket = a.Ticket
+			resolved++
+		}
+	}
+	return resolved
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/philgal/jtl/internal/csv"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAliases(t *testing.T) {
+	t.Cleanup(viper.Reset)
+	viper.Set("alias", map[string]any{
+		"jt1": "JIRATICKET-1",
+		"standup": map[string]any{
+			"ticket":   "MEET-7",
+			"comment":  "Daily standup",
+			"time":     "15m",
+			"estimate": "leave",
+		},
+	})
+
+	t.Run("Should read short and full aliases", func(t *testing.T) {
+		aliases, err := Aliases()
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"jt1", "standup"}, AliasNames(aliases))
+		assert.Equal(t, Alias{Name: "jt1", Ticket: "JIRATICKET-1"}, aliases["jt1"])
+		assert.Equal(t, Alias{Name: "standup", Ticket: "MEET-7", Comment: "Daily standup", TimeSpent: "15m", Estimate: "leave"}, aliases["standup"])
+	})
+
+	t.Run("Should resolve aliases ignoring case", func(t *testing.T) {
+		assert.Equal(t, "MEET-7", ResolveTicket("StandUp"))
+		assert.Equal(t, "OTHER-1", ResolveTicket("OTHER-1"))
+		assert.Equal(t, []string{"JIRATICKET-1", "SUP-1"}, ResolveTickets([]string{"jt1", "SUP-1"}))
+	})
+
+	t.Run("Should resolve aliases of records not pushed yet", func(t *testing.T) {
+		recs := []csv.Record{{Ticket: "jt1"}, {ID: "1", Ticket: "jt1"}, {Ticket: "SUP-1"}}
+
+		assert.Equal(t, 1, ResolveRecordAliases(recs))
+		assert.Equal(t, []string{"JIRATICKET-1", "jt1", "SUP-1"}, []string{recs[0].Ticket, recs[1].Ticket, recs[2].Ticket})
+	})
+
+	t.Run("Should use estimate of alias", func(t *testing.T) {
+		rules, err := NewEstimateRules()
+
+		assert.NoError(t, err)
+		assert.Equal(t, EstimateLeave, rules.For("MEET-7").Adjust)
+	})
+
+	t.Run("Should fail without ticket", func(t *testing.T) {
+		viper.Set("alias", map[string]any{"broken": map[string]any{"comment": "no ticket"}})
+
+		_, err := Aliases()
+
+		assert.ErrorContains(t, err, "alias.broken: ticket is not set")
+	})
+}
@@ -0,0 +1,192 @@
+package model
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Values of adjustEstimate query parameter of Jira worklog endpoint
+const (
+	EstimateAuto   = "auto"
+	EstimateLeave  = "leave"
+	EstimateNew    = "new"
+	EstimateManual = "manual"
+)
+
+// EstimateAdjustment tells Jira how to adjust the remaining estimate of an issue, when a worklog is created.
+// Zero value leaves the decision to Jira (auto).
+type EstimateAdjustment struct {
+	Adjust      string
+	NewEstimate string
+	ReduceBy    string
+}
+
+// ParseEstimateAdjustment parses a short form of adjustment: "auto", "leave", "new:<estimate>" or "manual:<reduce by>", e.g. "new:2d"
+func ParseEstimateAdjustment(s string) (EstimateAdjustment, error) {
+	adjust, value, _ := strings.Cut(strings.TrimSpace(s), ":")
+	ea := EstimateAdjustment{Adjust: strings.ToLower(strings.TrimSpace(adjust))}
+	switch ea.Adjust {
+	case EstimateNew:
+		ea.NewEstimate = strings.TrimSpace(value)
+	case EstimateManual:
+		ea.ReduceBy = strings.TrimSpace(value)
+	}
+	return ea, ea.Validate()
+}
+
+// Validate checks that the adjustment is known and has the value it requires
+func (ea EstimateAdjustment) Validate() error {
+	switch ea.Adjust {
+	case "", EstimateAuto, EstimateLeave:
+		return nil
+	case EstimateNew:
+		if ea.NewEstimate == "" {
+			return fmt.Errorf("estimate adjustment %q requires a new estimate, e.g. %v:2d", ea.Adjust, EstimateNew)
+		}
+		return nil
+	case EstimateManual:
+		if ea.ReduceBy == "" {
+			return fmt.Errorf("estimate adjustment %q requires a value to reduce by, e.g. %v:1h", ea.Adjust, EstimateManual)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown estimate adjustment %q, expected one of: %v, %v, %v, %v",
+			ea.Adjust, EstimateAuto, EstimateLeave, EstimateNew, EstimateManual)
+	}
+}
+
+// Query returns query parameters of the worklog endpoint for the adjustment
+func (ea EstimateAdjustment) Query() url.Values {
+	query := url.Values{}
+	if ea.Adjust == "" {
+		return query
+	}
+	query.Set("adjustEstimate", ea.Adjust)
+	switch ea.Adjust {
+	case EstimateNew:
+		query.Set("newEstimate", ea.NewEstimate)
+	case EstimateManual:
+		query.Set("reduceBy", ea.ReduceBy)
+	}
+	return query
+}
+
+// String returns the short form of the adjustment, accepted by ParseEstimateAdjustment
+func (ea EstimateAdjustment) String() string {
+	switch ea.Adjust {
+	case EstimateNew:
+		return ea.Adjust + ":" + ea.NewEstimate
+	case EstimateManual:
+		return ea.Adjust + ":" + ea.ReduceBy
+	default:
+		return ea.Adjust
+	}
+}
+
+// EstimateRules selects estimate adjustment for a ticket: a rule of the ticket's alias first, then of its project, then the default one.
+// Rules of aliases are read from estimate.aliases and from the aliases' estimate, the former take precedence.
+type EstimateRules struct {
+	Default  EstimateAdjustment
+	Projects map[string]EstimateAdjustment
+	Tickets  map[string]EstimateAdjustment
+}
+
+// NewEstimateRules reads estimate rules from config. A rule is either a short form or a map:
+//
+//	estimate:
+//	  default: auto
+//	  projects:
+//	    SUP: leave
+//	  aliases:
+//	    l666:
+//	      adjust: new
+//	      newEstimate: 2d
+func NewEstimateRules() (EstimateRules, error) {
+	rules := EstimateRules{Projects: map[string]EstimateAdjustment{}, Tickets: map[string]EstimateAdjustment{}}
+	var err error
+	if rules.Default, err = estimateRule(viper.Get("estimate.default")); err != nil {
+		return rules, fmt.Errorf("estimate.default: %w", err)
+	}
+	for project, v := range viper.GetStringMap("estimate.projects") {
+		if rules.Projects[strings.ToUpper(project)], err = estimateRule(v); err != nil {
+			return rules, fmt.Errorf("estimate.projects.%v: %w", project, err)
+		}
+	}
+	aliases, err := Aliases()
+	if err != nil {
+		return rules, err
+	}
+	for _, name := range AliasNames(aliases) {
+		if a := aliases[name]; a.Estimate != "" {
+			if rules.Tickets[strings.ToUpper(a.Ticket)], err = ParseEstimateAdjustment(a.Estimate); err != nil {
+				return rules, fmt.Errorf("alias.%v.estimate: %w", name, err)
+			}
+		}
+	}
+	for alias, v := range viper.GetStringMap("estimate.aliases") {
+		a, found := aliases[alias]
+		if !found {
+			return rules, fmt.Errorf("estimate.aliases.%v: alias is not defined", alias)
+		}
+		if rules.Tickets[strings.ToUpper(a.Ticket)], err = estimateRule(v); err != nil {
+			return rules, fmt.Errorf("estimate.aliases.%v: %w", alias, err)
+		}
+	}
+	return rules, nil
+}
+
+func estimateRule(v any) (EstimateAdjustment, error) {
+	switch rule := v.(type) {
+	case nil:
+		return EstimateAdjustment{}, nil
+	case string:
+		return ParseEstimateAdjustment(rule)
+	case map[string]any:
+		ea := EstimateAdjustment{
+			Adjust:      strings.ToLower(stringOf(rule, "adjust")),
+			NewEstimate: stringOf(rule, "newestimate"),
+			ReduceBy:    stringOf(rule, "reduceby"),
+		}
+		if ea.Adjust == "" && (ea.NewEstimate != "" || ea.ReduceBy != "") {
+			return ea, fmt.Errorf("rule requires adjust, one of: %v, %v, %v, %v", EstimateAuto, EstimateLeave, EstimateNew, EstimateManual)
+		}
+		return ea, ea.Validate()
+	default:
+		return EstimateAdjustment{}, fmt.Errorf("unsupported rule %v", v)
+	}
+}
+
+// valueOf returns the map's value by case-insensitive key, empty string if it's not set
+func valueOf(m map[string]any, key string) any {
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return ""
+}
+
+// stringOf returns the map's value by case-insensitive key as a string, empty if it's not set
+func stringOf(m map[string]any, key string) string {
+	v := valueOf(m, key)
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// For returns the adjustment for the ticket
+func (r EstimateRules) For(ticket string) EstimateAdjustment {
+	ticket = strings.ToUpper(ticket)
+	if ea, found := r.Tickets[ticket]; found {
+		return ea
+	}
+	project, _, _ := strings.Cut(ticket, "-")
+	if ea, found := r.Projects[project]; found {
+		return ea
+	}
+	return r.Default
+}
@@ -0,0 +1,82 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEstimateAdjustment(t *testing.T) {
+	tests := []struct {
+		in    string
+		want  EstimateAdjustment
+		isErr bool
+	}{
+		{"leave", EstimateAdjustment{Adjust: EstimateLeave}, false},
+		{"new:2d", EstimateAdjustment{Adjust: EstimateNew, NewEstimate: "2d"}, false},
+		{"Manual: 1h", EstimateAdjustment{Adjust: EstimateManual, ReduceBy: "1h"}, false},
+		{"new", EstimateAdjustment{Adjust: EstimateNew}, true},
+		{"shrink", EstimateAdjustment{Adjust: "shrink"}, true},
+	}
+	for _, tt := range tests {
+		t.Run("Should parse "+tt.in, func(t *testing.T) {
+			got, err := ParseEstimateAdjustment(tt.in)
+			assert.Equal(t, tt.isErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEstimateRules(t *testing.T) {
+	t.Cleanup(viper.Reset)
+	viper.Set("alias", map[string]any{"l666": "FEAT-666"})
+	viper.Set("estimate", map[string]any{
+		"default":  "auto",
+		"projects": map[string]any{"sup": "leave", "FEAT": "manual:30m"},
+		"aliases":  map[string]any{"l666": map[string]any{"adjust": "new", "newEstimate": "2d"}},
+	})
+
+	rules, err := NewEstimateRules()
+
+	assert.NoError(t, err)
+	t.Run("Should prefer alias rule", func(t *testing.T) {
+		assert.Equal(t, EstimateAdjustment{Adjust: EstimateNew, NewEstimate: "2d"}, rules.For("FEAT-666"))
+	})
+	t.Run("Should fall back to project rule", func(t *testing.T) {
+		assert.Equal(t, EstimateAdjustment{Adjust: EstimateLeave}, rules.For("SUP-1"))
+		assert.Equal(t, EstimateAdjustment{Adjust: EstimateManual, ReduceBy: "30m"}, rules.For("FEAT-1"))
+	})
+	t.Run("Should fall back to default rule", func(t *testing.T) {
+		assert.Equal(t, EstimateAdjustment{Adjust: EstimateAuto}, rules.For("OTHER-1"))
+	})
+	t.Run("Should build query parameters", func(t *testing.T) {
+		assert.Equal(t, "adjustEstimate=manual&reduceBy=30m", rules.For("FEAT-1").Query().Encode())
+		assert.Empty(t, EstimateAdjustment{}.Query())
+	})
+}
+
+func TestEstimateRule(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  map[string]any
+		want  EstimateAdjustment
+		isErr bool
+	}{
+		{"without keys", map[string]any{}, EstimateAdjustment{}, false},
+		{"with empty keys", map[string]any{"adjust": nil, "newEstimate": nil}, EstimateAdjustment{}, false},
+		{"without adjust", map[string]any{"newEstimate": "2d"}, EstimateAdjustment{NewEstimate: "2d"}, true},
+		{"without new estimate", map[string]any{"adjust": "new"}, EstimateAdjustment{Adjust: EstimateNew}, true},
+		{"without value to reduce by", map[string]any{"adjust": "manual", "reduceBy": nil}, EstimateAdjustment{Adjust: EstimateManual}, true},
+	}
+	for _, tt := range tests {
+		t.Run("Should read rule "+tt.name, func(t *testing.T) {
+			got, err := estimateRule(tt.rule)
+			assert.Equal(t, tt.isErr, err != nil)
+			assert.Equal(t, tt.want, got)
+			if err != nil {
+				assert.NotContains(t, err.Error(), "<nil>")
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package model
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/philgal/jtl/internal/config"
+	"github.com/philgal/jtl/internal/csv"
+)
+
+// RecordFilter selects records to push. Zero value selects all records.
+type RecordFilter struct {
+	// From and To are the first and the last day of records, inclusive
+	From time.Time
+	To   time.Time
+	// Before excludes records started at or after it, e.g. today's records still in progress
+	Before   time.Time
+	Tickets  []string
+	Projects []string
+	// SkipRemoved excludes removed records, so their worklogs are not deleted
+	SkipRemoved bool
+	// TickedRows and TickedRemoved, if not nil, select only records ticked by the user, see Tick:
+	// records by their row indexes and removed records by their IDs
+	TickedRows    map[int]bool
+	TickedRemoved map[string]bool
+}
+
+// Tick returns the filter, selecting only records of the ticked requests, so other targets mirror only them
+func (f RecordFilter) Tick(ticked JiraRequest) RecordFilter {
+	f.TickedRows, f.TickedRemoved = map[int]bool{}, map[string]bool{}
+	for _, row := range ticked {
+		if row.Method == http.MethodDelete {
+			f.TickedRemoved[row.WorklogID] = true
+		} else {
+			f.TickedRows[row.GetIdx()] = true
+		}
+	}
+	return f
+}
+
+// HasDates returns true if the filter selects records by their dates
+func (f RecordFilter) HasDates() bool {
+	return !f.From.IsZero() || !f.To.IsZero() || !f.Before.IsZero()
+}
+
+// Match returns true if the record is selected by the filter. Records with invalid dates are not selected by date filters.
+func (f RecordFilter) Match(r csv.Record) bool {
+	if f.TickedRows != nil && !f.TickedRows[r.GetIdx()] {
+		return false
+	}
+	if !f.MatchTicket(r.Ticket) {
+		return false
+	}
+	if !f.HasDates() {
+		return true
+	}
+	started, err := time.ParseInLocation(config.DefaultDateTimePattern, r.StartedTs, time.Local)
+	if err != nil {
+		return false
+	}
+	day := time.Date(started.Year(), started.Month(), started.Day(), 0, 0, 0, 0, time.Local)
+	return (f.From.IsZero() || !day.Before(f.From)) &&
+		(f.To.IsZero() || !day.After(f.To)) &&
+		(f.Before.IsZero() || started.Before(f.Before))
+}
+
+// MatchTicket returns true if the ticket is selected by ticket and project filters
+func (f RecordFilter) MatchTicket(ticket string) bool {
+	if len(f.Tickets) > 0 && !containsFold(f.Tickets, ticket) {
+		return false
+	}
+	project, _, _ := strings.Cut(ticket, "-")
+	return len(f.Projects) == 0 || containsFold(f.Projects, project)
+}
+
+// matchRemoved returns true if the removed record with the ID and ticket is selected. Their dates are unknown, so they are not selected by date filters.
+func (f RecordFilter) matchRemoved(id, ticket string) bool {
+	if f.TickedRemoved != nil && !f.TickedRemoved[id] {
+		return false
+	}
+	return !f.SkipRemoved && !f.HasDates() && f.MatchTicket(ticket)
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package model
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/philgal/jtl/internal/csv"
+	"github.com/philgal/jtl/internal/ledger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordFilter(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2020, 4, d, 0, 0, 0, 0, time.Local) }
+	rec := csv.Record{Ticket: "SUP-1", StartedTs: "15 Apr 2020 17:30"}
+	tests := []struct {
+		name   string
+		filter RecordFilter
+		want   bool
+	}{
+		{"Should match all with empty filter", RecordFilter{}, true},
+		{"Should match day", RecordFilter{From: day(15), To: day(15)}, true},
+		{"Should not match other day", RecordFilter{From: day(14), To: day(14)}, false},
+		{"Should match open range", RecordFilter{From: day(15)}, true},
+		{"Should not match after range", RecordFilter{To: day(14)}, false},
+		{"Should match before next day", RecordFilter{Before: day(16)}, true},
+		{"Should not match before the same day", RecordFilter{Before: day(15)}, false},
+		{"Should match ticket ignoring case", RecordFilter{Tickets: []string{"DEV-1", "sup-1"}}, true},
+		{"Should not match other ticket", RecordFilter{Tickets: []string{"SUP-10"}}, false},
+		{"Should match project", RecordFilter{Projects: []string{"sup"}}, true},
+		{"Should not match other project", RecordFilter{Projects: []string{"DEV"}, From: day(15)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.Match(rec))
+		})
+	}
+}
+
+func TestNewJiraRequestWithFilter(t *testing.T) {
+	pushed := csv.Record{ID: "1", Ticket: "SUP-1", StartedTs: "14 Apr 2020 10:00", TimeSpent: "1h", Comment: "pushed"}
+	l := &ledger.Ledger{Entries: map[string]ledger.Entry{}}
+	l.Track(pushed)
+	l.Track(csv.Record{ID: "2", Ticket: "SUP-2", StartedTs: "14 Apr 2020 12:00", TimeSpent: "1h"})
+	edited := pushed
+	edited.Comment = "edited"
+	recs := []csv.Record{
+		edited,
+		{Ticket: "SUP-3", StartedTs: "14 Apr 2020 14:00", TimeSpent: "1h"},
+		{Ticket: "DEV-1", StartedTs: "15 Apr 2020 10:00", TimeSpent: "1h"},
+	}
+
+	t.Run("Should create, update and delete records of the project", func(t *testing.T) {
+		filter := RecordFilter{Projects: []string{"SUP"}}
+
+		jr := append(NewJiraRequest(recs, filter), NewJiraSyncRequest(recs, l, filter)...)
+
+		assert.Equal(t, []string{http.MethodPost, http.MethodPut, http.MethodDelete}, []string{jr[0].Method, jr[1].Method, jr[2].Method})
+		assert.Equal(t, []string{"SUP-3", "SUP-1", "SUP-2"}, []string{jr[0].Jiraticket, jr[1].Jiraticket, jr[2].Jiraticket})
+	})
+
+	t.Run("Should not delete removed records filtering by date", func(t *testing.T) {
+		filter := RecordFilter{From: time.Date(2020, 4, 14, 0, 0, 0, 0, time.Local)}
+
+		jr := append(NewJiraRequest(recs, filter), NewJiraSyncRequest(recs, l, filter)...)
+
+		assert.Len(t, jr, 3)
+		assert.Equal(t, []string{"SUP-3", "DEV-1", "SUP-1"}, []string{jr[0].Jiraticket, jr[1].Jiraticket, jr[2].Jiraticket})
+	})
+
+	t.Run("Should not delete removed records, if skipped", func(t *testing.T) {
+		filter := RecordFilter{Projects: []string{"SUP"}, SkipRemoved: true}
+
+		jr := append(NewJiraRequest(recs, filter), NewJiraSyncRequest(recs, l, filter)...)
+
+		assert.Equal(t, []string{http.MethodPost, http.MethodPut}, []string{jr[0].Method, jr[1].Method})
+		assert.Len(t, jr, 2)
+	})
+
+}
+
+func TestNewJiraMirrorRequestWithTickedRecords(t *testing.T) {
+	file := csv.File{}
+	for _, r := range []csv.Record{
+		{ID: "1", Ticket: "SUP-1", StartedTs: "14 Apr 2020 10:00", TimeSpent: "1h", Comment: "edited"},
+		{ID: "10", Ticket: "SUP-3", StartedTs: "14 Apr 2020 14:00", TimeSpent: "1h"},
+		{ID: "11", Ticket: "SUP-4", StartedTs: "14 Apr 2020 16:00", TimeSpent: "1h"},
+	} {
+		file.AddRecord(r)
+	}
+	mirror := &ledger.Ledger{Entries: map[string]ledger.Entry{}}
+	mirror.TrackMirror(csv.Record{ID: "1", Ticket: "SUP-1", StartedTs: "14 Apr 2020 10:00", TimeSpent: "1h"}, "B-1")
+	mirror.TrackMirror(csv.Record{ID: "2", Ticket: "SUP-2", StartedTs: "14 Apr 2020 12:00", TimeSpent: "1h"}, "B-2")
+	mirror.TrackMirror(csv.Record{ID: "3", Ticket: "SUP-5", StartedTs: "14 Apr 2020 18:00", TimeSpent: "1h"}, "B-3")
+	// SUP-3 and removal of SUP-2 are ticked, edited SUP-1, SUP-4 and removal of SUP-5 are not
+	ticked := JiraRequest{
+		{_rowIdx: 1, Method: http.MethodPost, Jiraticket: "SUP-3"},
+		{_rowIdx: -1, Method: http.MethodDelete, WorklogID: "2", Jiraticket: "SUP-2"},
+	}
+
+	t.Run("Should mirror only ticked records", func(t *testing.T) {
+		jr := NewJiraMirrorRequest(file.Records, mirror, RecordFilter{}.Tick(ticked))
+
+		assert.Len(t, jr, 2)
+		assert.Equal(t, []string{http.MethodPost, http.MethodDelete}, []string{jr[0].Method, jr[1].Method})
+		assert.Equal(t, []string{"SUP-3", "B-2"}, []string{jr[0].Jiraticket, jr[1].WorklogID})
+	})
+
+	t.Run("Should mirror all records if nothing is ticked", func(t *testing.T) {
+		jr := NewJiraMirrorRequest(file.Records, mirror, RecordFilter{})
+
+		assert.Len(t, jr, 5)
+	})
+}
@@ -1,21 +1,33 @@
 package model
 
 import (
+	"encoding/base64"
 	"fmt"
+	"maps"
+	"net/http"
 	"os"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/philgal/jtl/internal/csv"
+	"github.com/philgal/jtl/internal/ledger"
 	"github.com/spf13/viper"
 )
 
 type JiraRequestRow struct {
-	_rowIdx    int
+	_rowIdx int
+	// Method is POST for new records, PUT for modified and DELETE for removed pushed records
+	Method     string
+	WorklogID  string
 	Jiraticket string
 	Timespent  string
 	Comment    string
 	Started    string
+	// Visibility restricts who can see the worklog, e.g. "role:Developers"
+	Visibility string
+	// Estimate is an adjustment of the issue's remaining estimate, applied when the worklog is created
+	Estimate EstimateAdjustment
 }
 
 func (rr *JiraRequestRow) GetIdx() int {
@@ -24,44 +36,207 @@ func (rr *JiraRequestRow) GetIdx() int {
 
 type JiraRequest []JiraRequestRow
 
-// NewJiraRequest creates JiraRequest from CsvRecords
-func NewJiraRequest(recs []csv.Record) JiraRequest {
+// NewJiraRequest creates JiraRequest from CsvRecords, selected by the filter
+func NewJiraRequest(recs []csv.Record, filter RecordFilter) JiraRequest {
 	jr := JiraRequest{}
-	for _, row := range csv.Filter(recs, func(r csv.Record) bool { return !r.IsPushed() }) {
+	for _, row := range csv.Filter(recs, func(r csv.Record) bool { return !r.IsPushed() && filter.Match(r) }) {
 		//Rows with IDs are pushed, don't them into request
 		req := JiraRequestRow{
 			_rowIdx:    row.GetIdx(),
+			Method:     http.MethodPost,
 			Jiraticket: row.Ticket,
 			Started:    row.StartedTs,
 			Comment:    row.Comment,
 			Timespent:  row.TimeSpent,
+			Visibility: row.Visibility,
 		}
 		jr = append(jr, req)
 	}
 	return jr
 }
 
+// NewJiraSyncRequest creates JiraRequest for pushed records, changed since they were pushed:
+// PUT for records modified in the data file and DELETE for records removed from it.
+// Pushed records, unknown to the ledger, are neither updated nor deleted.
+// Only records selected by the filter are synced, removed records are not selected by date filters.
+func NewJiraSyncRequest(recs []csv.Record, l *ledger.Ledger, filter RecordFilter) JiraRequest {
+	jr := JiraRequest{}
+	for _, row := range csv.Filter(recs, func(r csv.Record) bool { return l.IsModified(r) && filter.Match(r) }) {
+		if entry := l.Entries[row.ID]; entry.Ticket != row.Ticket {
+			fmt.Printf("Ticket of pushed record %v changed from %v to %v, worklogs can't be moved between tickets. Clear its ID to push it as a new one.\n",
+				row.ID, entry.Ticket, row.Ticket)
+			continue
+		}
+		jr = append(jr, JiraRequestRow{
+			_rowIdx:    row.GetIdx(),
+			Method:     http.MethodPut,
+			WorklogID:  row.ID,
+			Jiraticket: row.Ticket,
+			Started:    row.StartedTs,
+			Comment:    row.Comment,
+			Timespent:  row.TimeSpent,
+			Visibility: row.Visibility,
+		})
+	}
+	for _, entry := range l.Removed(recs) {
+		if !filter.matchRemoved(entry.ID, entry.Ticket) {
+			continue
+		}
+		jr = append(jr, JiraRequestRow{
+			_rowIdx:    -1, // removed records are not in the file anymore
+			Method:     http.MethodDelete,
+			WorklogID:  entry.ID,
+			Jiraticket: entry.Ticket,
+		})
+	}
+	return jr
+}
+
+// NewJiraMirrorRequest creates JiraRequest, mirroring pushed records to another target with ledger l, which entries are keyed by records' IDs:
+// POST for pushed records, which are not mirrored yet, PUT for records changed since they were mirrored and DELETE for mirrored records removed from the data file.
+// Only records selected by the filter are mirrored, as in NewJiraSyncRequest, removed records are selected by IDs of their records, not of the mirrored worklogs.
+func NewJiraMirrorRequest(recs []csv.Record, l *ledger.Ledger, filter RecordFilter) JiraRequest {
+	jr := JiraRequest{}
+	for _, row := range csv.Filter(recs, func(r csv.Record) bool { return r.IsPushed() && filter.Match(r) }) {
+		req := JiraRequestRow{
+			_rowIdx:    row.GetIdx(),
+			Method:     http.MethodPost,
+			Jiraticket: row.Ticket,
+			Started:    row.StartedTs,
+			Comment:    row.Comment,
+			Timespent:  row.TimeSpent,
+			Visibility: row.Visibility,
+		}
+		if entry, found := l.Entries[row.ID]; found {
+			if entry.Hash == row.Hash() {
+				continue
+			}
+			req.Method, req.WorklogID = http.MethodPut, entry.ID
+		}
+		jr = append(jr, req)
+	}
+	for _, id := range l.RemovedIDs(recs) {
+		entry := l.Entries[id]
+		if !filter.matchRemoved(id, entry.Ticket) {
+			continue
+		}
+		jr = append(jr, JiraRequestRow{
+			_rowIdx:    -1,
+			Method:     http.MethodDelete,
+			WorklogID:  entry.ID,
+			Jiraticket: entry.Ticket,
+		})
+	}
+	return jr
+}
+
+// JiraResponse is an outcome of pushing a single JiraRequestRow.
+// On success it holds the created worklog, otherwise HTTP status and error details returned by Jira.
 type JiraResponse struct {
+	// Target is a name of the push target, the response is received from
+	Target    string
 	RowIdx    int
+	Method    string
+	Ticket    string
 	Id        string
 	IssueId   string
 	Timespent string
 	Comment   string
 	Started   string
 	IsSuccess bool
+	// IsAdopted is true if the worklog already existed in Jira and has not been created again
+	IsAdopted     bool
+	Attempts      int
+	StatusCode    int
+	ErrorMessages []string
+	Errors        map[string]string
+	Err           error
 }
 
+// FailureReason returns a human-readable reason of a failed push, combining Jira error messages, field errors and transport errors
+func (r JiraResponse) FailureReason() string {
+	if r.IsSuccess {
+		return ""
+	}
+	reasons := slices.Clone(r.ErrorMessages)
+	for _, field := range slices.Sorted(maps.Keys(r.Errors)) {
+		reasons = append(reasons, fmt.Sprintf("%v: %v", field, r.Errors[field]))
+	}
+	if len(reasons) == 0 && r.Err != nil {
+		reasons = append(reasons, r.Err.Error())
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// Supported values of auth.type config
+const (
+	// AuthBasic is a Basic auth with username and password
+	AuthBasic = "basic"
+	// AuthBearer is a Bearer auth with a Personal Access Token (Jira Data Center)
+	AuthBearer = "bearer"
+	// AuthPAT is an alias of AuthBearer
+	AuthPAT = "pat"
+	// AuthCloudToken is a Basic auth with email as username and API token as password (Jira Cloud)
+	AuthCloudToken = "cloud-token"
+)
+
 type Credentials struct {
+	Type     string
 	Username string
 	Password string
+	Token    string
 }
 
 func (creds *Credentials) Trim() *Credentials {
-	return &Credentials{strings.TrimSpace(creds.Username), strings.TrimSpace(creds.Password)}
+	return &Credentials{
+		Type:     strings.ToLower(strings.TrimSpace(creds.Type)),
+		Username: strings.TrimSpace(creds.Username),
+		Password: strings.TrimSpace(creds.Password),
+		Token:    strings.TrimSpace(creds.Token),
+	}
+}
+
+// AuthType returns normalized auth type, AuthBasic if not set
+func (creds *Credentials) AuthType() string {
+	switch creds.Type {
+	case "":
+		return AuthBasic
+	case AuthPAT:
+		return AuthBearer
+	default:
+		return creds.Type
+	}
 }
 
+// IsValid returns true if all the secrets required by the auth type are set
 func (creds *Credentials) IsValid() bool {
-	return creds.Username != "" && creds.Password != ""
+	switch creds.AuthType() {
+	case AuthBearer:
+		return creds.Token != ""
+	case AuthCloudToken:
+		return creds.Username != "" && creds.Token != ""
+	default:
+		return creds.Username != "" && creds.Password != ""
+	}
+}
+
+// AuthorizationHeader returns a value of Authorization header for the auth type
+func (creds *Credentials) AuthorizationHeader() (string, error) {
+	switch creds.AuthType() {
+	case AuthBasic:
+		return "Basic " + basicAuth(creds.Username, creds.Password), nil
+	case AuthCloudToken:
+		return "Basic " + basicAuth(creds.Username, creds.Token), nil
+	case AuthBearer:
+		return "Bearer " + creds.Token, nil
+	default:
+		return "", fmt.Errorf("unsupported auth type %q, expected one of: %v, %v, %v", creds.Type, AuthBasic, AuthBearer, AuthCloudToken)
+	}
+}
+
+func basicAuth(username, password string) string {
+	auth := username + ":" + password
+	return base64.StdEncoding.EncodeToString([]byte(auth))
 }
 
 func ValidateJiraTicketFormat(ticket string) {
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// DefaultVisibility returns visibility configured for the ticket's project, empty if it's not configured:
+//
+//	visibility:
+//	  projects:
+//	    SUP: role:Developers
+func DefaultVisibility(ticket string) string {
+	project, _, _ := strings.Cut(strings.ToUpper(ticket), "-")
+	for key, v := range viper.GetStringMapString("visibility.projects") {
+		if strings.EqualFold(key, project) {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,188 @@
+package picker
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Check lets the user tick lines, interactively if stdin and stderr are terminals, or by numbers otherwise.
+// All lines are ticked initially. Returns indexes of ticked lines.
+func Check(lines []string) ([]int, error) {
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	in, out := int(os.Stdin.Fd()), int(os.Stderr.Fd())
+	if !term.IsTerminal(in) || !term.IsTerminal(out) {
+		return CheckNumbered(lines, os.Stdin, os.Stderr)
+	}
+	state, err := term.MakeRaw(in)
+	if err != nil {
+		return CheckNumbered(lines, os.Stdin, os.Stderr)
+	}
+	defer term.Restore(in, state)
+	height := 15
+	if _, h, err := term.GetSize(out); err == nil && h > 3 {
+		height = min(height, h-2)
+	}
+	return newChecklist(lines, height).run(os.Stdin, os.Stderr)
+}
+
+// CheckNumbered writes numbered lines and reads numbers and ranges of ticked ones, e.g. 1,3-5, or all
+func CheckNumbered(lines []string, r io.Reader, w io.Writer) ([]int, error) {
+	for i, line := range lines {
+		fmt.Fprintf(w, "%3d) %v\n", i+1, line)
+	}
+	fmt.Fprintf(w, "Pick numbers, e.g. 1,3-5, or all [1-%v]: ", len(lines))
+	input, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && input == "" {
+		return nil, ErrCancelled
+	}
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, ErrCancelled
+	}
+	return parseNumbers(input, len(lines))
+}
+
+// parseNumbers parses comma separated numbers and ranges from 1 to n into sorted indexes
+func parseNumbers(input string, n int) ([]int, error) {
+	ticked := make([]bool, n)
+	if strings.EqualFold(input, "all") {
+		for i := range ticked {
+			ticked[i] = true
+		}
+	}
+	for _, part := range strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' }) {
+		if strings.EqualFold(part, "all") {
+			continue
+		}
+		first, last, isRange := strings.Cut(part, "-")
+		from, err := strconv.Atoi(first)
+		to := from
+		if err == nil && isRange {
+			to, err = strconv.Atoi(last)
+		}
+		if err != nil || from < 1 || to > n || from > to {
+			return nil, fmt.Errorf("invalid choice %q", part)
+		}
+		for i := from; i <= to; i++ {
+			ticked[i-1] = true
+		}
+	}
+	var indexes []int
+	for i, t := range ticked {
+		if t {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes, nil
+}
+
+// checklist is an interactive list of lines to tick. Arrows move the cursor, Space ticks the line, a ticks all or none,
+// Enter confirms ticked lines, Esc or Ctrl+C cancels.
+type checklist struct {
+	lines  []string
+	ticked []bool
+	cursor int
+	height int
+	screen screen
+}
+
+func newChecklist(lines []string, height int) *checklist {
+	ticked := make([]bool, len(lines))
+	for i := range ticked {
+		ticked[i] = true
+	}
+	return &checklist{lines: lines, ticked: ticked, height: height}
+}
+
+func (c *checklist) run(r io.Reader, w io.Writer) ([]int, error) {
+	reader := bufio.NewReader(r)
+	c.render(w)
+	defer c.screen.clear(w)
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return nil, ErrCancelled
+		}
+		switch b {
+		case keyCtrlC:
+			return nil, ErrCancelled
+		case keyEnter, '\n':
+			var indexes []int
+			for i, t := range c.ticked {
+				if t {
+					indexes = append(indexes, i)
+				}
+			}
+			return indexes, nil
+		case keyEsc:
+			if reader.Buffered() == 0 {
+				return nil, ErrCancelled
+			}
+			if next, _ := reader.ReadByte(); next == '[' {
+				switch arrow, _ := reader.ReadByte(); arrow {
+				case 'A':
+					c.move(-1)
+				case 'B':
+					c.move(1)
+				}
+			}
+		case 'k', keyCtrlP:
+			c.move(-1)
+		case 'j', keyCtrlN:
+			c.move(1)
+		case ' ', 'x':
+			c.ticked[c.cursor] = !c.ticked[c.cursor]
+		case 'a':
+			all := !c.allTicked()
+			for i := range c.ticked {
+				c.ticked[i] = all
+			}
+		}
+		c.render(w)
+	}
+}
+
+func (c *checklist) move(delta int) {
+	c.cursor = max(0, min(len(c.lines)-1, c.cursor+delta))
+}
+
+func (c *checklist) allTicked() bool {
+	for _, t := range c.ticked {
+		if !t {
+			return false
+		}
+	}
+	return true
+}
+
+// render redraws the visible window of lines and the help line
+func (c *checklist) render(w io.Writer) {
+	first := max(0, c.cursor-c.height+1)
+	last := min(len(c.lines), first+c.height)
+	ticked := 0
+	for _, t := range c.ticked {
+		if t {
+			ticked++
+		}
+	}
+	var lines []string
+	for i := first; i < last; i++ {
+		cursor, box := "  ", "[ ]"
+		if i == c.cursor {
+			cursor = "> "
+		}
+		if c.ticked[i] {
+			box = "[x]"
+		}
+		lines = append(lines, cursor+box+" "+c.lines[i])
+	}
+	c.screen.draw(w, lines, fmt.Sprintf("%v/%v ticked, space: tick, a: all/none, enter: confirm, esc: cancel", ticked, len(c.lines)))
+}
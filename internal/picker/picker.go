@@ -0,0 +1,256 @@
+package picker
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// ErrCancelled is returned when the user doesn't pick an item
+var ErrCancelled = errors.New("nothing picked")
+
+// Item is an issue to pick
+type Item struct {
+	Key     string
+	Summary string
+}
+
+func (i Item) String() string {
+	if i.Summary == "" {
+		return i.Key
+	}
+	return i.Key + "  " + i.Summary
+}
+
+// Filter returns items fuzzy matching the query, i.e. containing its characters in order, case-insensitive.
+// Items with closer and earlier matches go first.
+func Filter(items []Item, query string) []Item {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return items
+	}
+	type scored struct {
+		item  Item
+		score int
+	}
+	var matched []scored
+	for _, item := range items {
+		if score, ok := match(item.String(), query); ok {
+			matched = append(matched, scored{item, score})
+		}
+	}
+	slices.SortStableFunc(matched, func(a, b scored) int { return a.score - b.score })
+	filtered := make([]Item, len(matched))
+	for i, m := range matched {
+		filtered[i] = m.item
+	}
+	return filtered
+}
+
+// match returns a score of the query matching the text, lower is better: position of the first match plus characters skipped between matches
+func match(text, query string) (int, bool) {
+	t := []rune(strings.ToLower(text))
+	score, prev := 0, -1
+	for _, q := range strings.ToLower(query) {
+		if unicode.IsSpace(q) {
+			continue
+		}
+		i := slices.Index(t[prev+1:], q)
+		if i < 0 {
+			return 0, false
+		}
+		score += i
+		prev += i + 1
+	}
+	return score, true
+}
+
+// Pick lets the user pick an item, interactively filtering them if stdin and stderr are terminals, or from a numbered list otherwise.
+// The list is written to stderr, so that the picked key can be captured from stdout.
+func Pick(items []Item) (Item, error) {
+	if len(items) == 0 {
+		return Item{}, ErrCancelled
+	}
+	in, out := int(os.Stdin.Fd()), int(os.Stderr.Fd())
+	if !term.IsTerminal(in) || !term.IsTerminal(out) {
+		return PickNumbered(items, os.Stdin, os.Stderr)
+	}
+	state, err := term.MakeRaw(in)
+	if err != nil {
+		return PickNumbered(items, os.Stdin, os.Stderr)
+	}
+	defer term.Restore(in, state)
+	height := 10
+	if _, h, err := term.GetSize(out); err == nil && h > 3 {
+		height = min(height, h-2)
+	}
+	return newFuzzy(items, height).run(os.Stdin, os.Stderr)
+}
+
+// PickNumbered writes numbered items and reads the number of the picked one
+func PickNumbered(items []Item, r io.Reader, w io.Writer) (Item, error) {
+	for i, item := range items {
+		fmt.Fprintf(w, "%3d) %v\n", i+1, item)
+	}
+	fmt.Fprintf(w, "Pick a number [1-%v]: ", len(items))
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && line == "" {
+		return Item{}, ErrCancelled
+	}
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return Item{}, ErrCancelled
+	}
+	n, err := strconv.Atoi(line)
+	if err != nil || n < 1 || n > len(items) {
+		return Item{}, fmt.Errorf("invalid choice %q", line)
+	}
+	return items[n-1], nil
+}
+
+// fuzzy is an interactive list, filtered as the user types. Arrows move the selection, Enter picks it, Esc or Ctrl+C cancels.
+type fuzzy struct {
+	items    []Item
+	filtered []Item
+	query    []rune
+	selected int
+	height   int
+	screen   screen
+}
+
+func newFuzzy(items []Item, height int) *fuzzy {
+	return &fuzzy{items: items, filtered: items, height: height}
+}
+
+const (
+	keyCtrlC     = 3
+	keyEnter     = '\r'
+	keyEsc       = 27
+	keyBackspace = 127
+	keyCtrlH     = 8
+	keyCtrlN     = 14
+	keyCtrlP     = 16
+)
+
+func (f *fuzzy) run(r io.Reader, w io.Writer) (Item, error) {
+	reader := bufio.NewReader(r)
+	f.render(w)
+	defer f.screen.clear(w)
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return Item{}, ErrCancelled
+		}
+		switch b {
+		case keyCtrlC:
+			return Item{}, ErrCancelled
+		case keyEnter, '\n':
+			if len(f.filtered) == 0 {
+				continue
+			}
+			return f.filtered[f.selected], nil
+		case keyEsc:
+			// arrows are sent as ESC [ A/B, a single ESC cancels
+			if reader.Buffered() == 0 {
+				return Item{}, ErrCancelled
+			}
+			if next, _ := reader.ReadByte(); next == '[' {
+				switch arrow, _ := reader.ReadByte(); arrow {
+				case 'A':
+					f.move(-1)
+				case 'B':
+					f.move(1)
+				}
+			}
+		case keyCtrlP:
+			f.move(-1)
+		case keyCtrlN:
+			f.move(1)
+		case keyBackspace, keyCtrlH:
+			if len(f.query) > 0 {
+				f.setQuery(f.query[:len(f.query)-1])
+			}
+		default:
+			if b < ' ' {
+				continue
+			}
+			reader.UnreadByte()
+			ch, _, err := reader.ReadRune()
+			if err != nil {
+				return Item{}, ErrCancelled
+			}
+			if ch != utf8.RuneError {
+				f.setQuery(append(f.query, ch))
+			}
+		}
+		f.render(w)
+	}
+}
+
+func (f *fuzzy) move(delta int) {
+	f.selected = max(0, min(len(f.filtered)-1, f.selected+delta))
+}
+
+func (f *fuzzy) setQuery(query []rune) {
+	f.query = query
+	f.filtered = Filter(f.items, string(query))
+	f.selected = 0
+}
+
+// render redraws the visible window of filtered items and the prompt
+func (f *fuzzy) render(w io.Writer) {
+	first := max(0, f.selected-f.height+1)
+	last := min(len(f.filtered), first+f.height)
+	var lines []string
+	for i := first; i < last; i++ {
+		marker := "  "
+		if i == f.selected {
+			marker = "> "
+		}
+		lines = append(lines, marker+f.filtered[i].String())
+	}
+	f.screen.draw(w, lines, fmt.Sprintf("%v/%v > %v", len(f.filtered), len(f.items), string(f.query)))
+}
+
+// screen draws lines in place of the previously drawn ones, in raw mode of a terminal
+type screen struct {
+	// rendered is a number of lines written by the last draw, besides the footer
+	rendered int
+}
+
+func (s *screen) draw(w io.Writer, lines []string, footer string) {
+	sb := strings.Builder{}
+	s.erase(&sb)
+	for _, line := range lines {
+		sb.WriteString(line + "\r\n")
+	}
+	sb.WriteString(footer)
+	s.rendered = len(lines)
+	io.WriteString(w, sb.String())
+}
+
+// clear erases the drawn lines when the picker is done
+func (s *screen) clear(w io.Writer) {
+	sb := strings.Builder{}
+	s.erase(&sb)
+	s.rendered = 0
+	io.WriteString(w, sb.String())
+}
+
+// erase moves the cursor to the first drawn line and clears the screen below it
+func (s *screen) erase(sb *strings.Builder) {
+	sb.WriteString("\r")
+	if s.rendered > 0 {
+		fmt.Fprintf(sb, "\x1b[%dA", s.rendered)
+	}
+	sb.WriteString("\x1b[J")
+}
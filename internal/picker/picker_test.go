@@ -0,0 +1,103 @@
+package picker
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var items = []Item{
+	{Key: "SUP-1", Summary: "Support request"},
+	{Key: "DEV-12", Summary: "Payment API"},
+	{Key: "DEV-2", Summary: "Sprint planning"},
+}
+
+func TestFilter(t *testing.T) {
+	t.Run("Should return all items for empty query", func(t *testing.T) {
+		assert.Equal(t, items, Filter(items, " "))
+	})
+
+	t.Run("Should match characters in order ignoring case, closer matches first", func(t *testing.T) {
+		assert.Equal(t, []Item{items[1], items[2]}, Filter(items, "dev"))
+		assert.Equal(t, []Item{items[1]}, Filter(items, "pay api"))
+		assert.Equal(t, []Item{items[0], items[2]}, Filter(items, "sp"))
+		assert.Empty(t, Filter(items, "xyz"))
+	})
+}
+
+func TestPickNumbered(t *testing.T) {
+	t.Run("Should pick item by number", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		picked, err := PickNumbered(items, strings.NewReader("2\n"), out)
+
+		assert.NoError(t, err)
+		assert.Equal(t, items[1], picked)
+		assert.Contains(t, out.String(), "  2) DEV-12  Payment API\n")
+	})
+
+	t.Run("Should cancel on empty input and reject invalid number", func(t *testing.T) {
+		_, err := PickNumbered(items, strings.NewReader(""), &bytes.Buffer{})
+		assert.ErrorIs(t, err, ErrCancelled)
+
+		_, err = PickNumbered(items, strings.NewReader("4\n"), &bytes.Buffer{})
+		assert.ErrorContains(t, err, "invalid choice")
+	})
+}
+
+func TestFuzzy(t *testing.T) {
+	t.Run("Should filter as typed and pick selected with arrows", func(t *testing.T) {
+		picked, err := newFuzzy(items, 10).run(strings.NewReader("dex\x7f\x1b[B\r"), &bytes.Buffer{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, items[2], picked)
+	})
+
+	t.Run("Should cancel on Ctrl+C", func(t *testing.T) {
+		_, err := newFuzzy(items, 10).run(strings.NewReader("d\x03"), &bytes.Buffer{})
+
+		assert.ErrorIs(t, err, ErrCancelled)
+	})
+}
+
+func TestCheckNumbered(t *testing.T) {
+	lines := []string{"one", "two", "three", "four"}
+
+	t.Run("Should tick numbers and ranges", func(t *testing.T) {
+		ticked, err := CheckNumbered(lines, strings.NewReader("1, 3-4\n"), &bytes.Buffer{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{0, 2, 3}, ticked)
+	})
+
+	t.Run("Should tick all", func(t *testing.T) {
+		ticked, err := CheckNumbered(lines, strings.NewReader("all\n"), &bytes.Buffer{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{0, 1, 2, 3}, ticked)
+	})
+
+	t.Run("Should reject numbers out of range", func(t *testing.T) {
+		_, err := CheckNumbered(lines, strings.NewReader("3-5\n"), &bytes.Buffer{})
+
+		assert.ErrorContains(t, err, `invalid choice "3-5"`)
+	})
+}
+
+func TestChecklist(t *testing.T) {
+	t.Run("Should start with all ticked and untick with space", func(t *testing.T) {
+		ticked, err := newChecklist([]string{"one", "two", "three"}, 10).run(strings.NewReader("\x1b[B \r"), &bytes.Buffer{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{0, 2}, ticked)
+	})
+
+	t.Run("Should untick all and tick one", func(t *testing.T) {
+		ticked, err := newChecklist([]string{"one", "two", "three"}, 10).run(strings.NewReader("ajj \r"), &bytes.Buffer{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{2}, ticked)
+	})
+}
@@ -0,0 +1,97 @@
+package reconcile
+
+import (
+	"strings"
+
+	"github.com/philgal/jtl/internal/csv"
+	"github.com/philgal/jtl/internal/duration"
+	"github.com/philgal/jtl/internal/jira"
+)
+
+// Change is a field of a pushed record, which differs remotely
+type Change struct {
+	Field  string `json:"field"`
+	Local  string `json:"local"`
+	Remote string `json:"remote"`
+}
+
+// Modified is a pushed record, changed in Jira after it was pushed
+type Modified struct {
+	Record  csv.Record `json:"record"`
+	Changes []Change   `json:"changes"`
+}
+
+// Result is a difference between records in the data file and worklogs in Jira
+type Result struct {
+	// MissingRemotely are pushed records, which worklogs are deleted in Jira
+	MissingRemotely []csv.Record `json:"missingRemotely"`
+	// Modified are pushed records with time spent, started, comment or visibility changed in Jira
+	Modified []Modified `json:"modified"`
+	// Unpushed are local records, not pushed to Jira yet
+	Unpushed []csv.Record `json:"unpushed"`
+	// RemoteOnly are user's worklogs in Jira, which are not in the data file
+	RemoteOnly []csv.Record `json:"remoteOnly"`
+}
+
+// IsEmpty returns true if local and remote data are in sync
+func (r Result) IsEmpty() bool {
+	return len(r.MissingRemotely) == 0 && len(r.Modified) == 0 && len(r.Unpushed) == 0 && len(r.RemoteOnly) == 0
+}
+
+// Diff compares local records with remote worklogs.
+// pushed holds a remote worklog for every ID of a pushed record, nil if the worklog has not been found.
+// userWorklogs are all user's worklogs in the period of the data file, used to find remote-only worklogs.
+func Diff(local []csv.Record, pushed map[string]*jira.Worklog, userWorklogs []jira.Worklog) Result {
+	result := Result{
+		MissingRemotely: []csv.Record{},
+		Modified:        []Modified{},
+		Unpushed:        []csv.Record{},
+		RemoteOnly:      []csv.Record{},
+	}
+	localIds := map[string]bool{}
+	for _, rec := range local {
+		if !rec.IsPushed() {
+			result.Unpushed = append(result.Unpushed, rec)
+			continue
+		}
+		localIds[rec.ID] = true
+		worklog := pushed[rec.ID]
+		if worklog == nil {
+			result.MissingRemotely = append(result.MissingRemotely, rec)
+			continue
+		}
+		if changes := Compare(rec, jira.RecordFromWorklog(*worklog)); len(changes) > 0 {
+			result.Modified = append(result.Modified, Modified{Record: rec, Changes: changes})
+		}
+	}
+	for _, w := range userWorklogs {
+		if !localIds[w.ID] {
+			localIds[w.ID] = true
+			result.RemoteOnly = append(result.RemoteOnly, jira.RecordFromWorklog(w))
+		}
+	}
+	return result
+}
+
+// Compare returns changed fields of a local record compared to remote one.
+// Time spent is compared by duration, so "90m" equals "1h 30m", comments ignore surrounding whitespace and line endings.
+func Compare(local, remote csv.Record) []Change {
+	var changes []Change
+	if duration.ToMinutes(local.TimeSpent) != duration.ToMinutes(remote.TimeSpent) {
+		changes = append(changes, Change{"timeSpent", local.TimeSpent, remote.TimeSpent})
+	}
+	if !duration.ParseTime(local.StartedTs).Equal(duration.ParseTime(remote.StartedTs).Time) {
+		changes = append(changes, Change{"started", local.StartedTs, remote.StartedTs})
+	}
+	if normalizeComment(local.Comment) != normalizeComment(remote.Comment) {
+		changes = append(changes, Change{"comment", local.Comment, remote.Comment})
+	}
+	if !strings.EqualFold(strings.TrimSpace(local.Visibility), strings.TrimSpace(remote.Visibility)) {
+		changes = append(changes, Change{"visibility", local.Visibility, remote.Visibility})
+	}
+	return changes
+}
+
+func normalizeComment(c string) string {
+	return strings.TrimSpace(strings.ReplaceAll(c, "\r\n", "\n"))
+}
@@ -0,0 +1,47 @@
+package reconcile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/philgal/jtl/internal/config"
+	"github.com/philgal/jtl/internal/csv"
+	"github.com/philgal/jtl/internal/jira"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	local := []csv.Record{
+		{ID: "1", StartedTs: "14 Apr 2020 11:30", Comment: "in sync", TimeSpent: "90m", Ticket: "TICKET-1"},
+		{ID: "2", StartedTs: "14 Apr 2020 13:00", Comment: "deleted in Jira", TimeSpent: "1h", Ticket: "TICKET-1"},
+		{ID: "3", StartedTs: "15 Apr 2020 09:00", Comment: "typo", TimeSpent: "2h", Ticket: "TICKET-2"},
+		{ID: "", StartedTs: "16 Apr 2020 09:00", Comment: "local only", TimeSpent: "1h", Ticket: "TICKET-2"},
+	}
+	worklog := func(id, issue, started, comment string, seconds int) jira.Worklog {
+		return jira.Worklog{ID: id, Issue: issue, Started: started, Comment: jira.Comment{Text: comment}, TimeSpentSeconds: seconds}
+	}
+	inSync := worklog("1", "TICKET-1", localTs("14 Apr 2020 11:30"), "in sync\n", 5400)
+	changed := worklog("3", "TICKET-2", localTs("15 Apr 2020 10:00"), "fixed typo", 7200)
+	remoteOnly := worklog("4", "TICKET-3", localTs("17 Apr 2020 09:00"), "logged in Jira UI", 1800)
+
+	result := Diff(local,
+		map[string]*jira.Worklog{"1": &inSync, "2": nil, "3": &changed},
+		[]jira.Worklog{inSync, changed, remoteOnly})
+
+	assert.Equal(t, []csv.Record{local[1]}, result.MissingRemotely)
+	assert.Equal(t, []Modified{{Record: local[2], Changes: []Change{
+		{"started", "15 Apr 2020 09:00", "15 Apr 2020 10:00"},
+		{"comment", "typo", "fixed typo"},
+	}}}, result.Modified)
+	assert.Equal(t, []csv.Record{local[3]}, result.Unpushed)
+	assert.Len(t, result.RemoteOnly, 1)
+	assert.Equal(t, "4", result.RemoteOnly[0].ID)
+	assert.Equal(t, "30m", result.RemoteOnly[0].TimeSpent)
+	assert.False(t, result.IsEmpty())
+}
+
+// localTs converts data file timestamp into Jira timestamp in local time zone
+func localTs(ts string) string {
+	started, _ := time.ParseInLocation(config.DefaultDateTimePattern, ts, time.Local)
+	return jira.FormatTime(started)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"slices"
 	"time"
 
 	"github.com/jedib0t/go-pretty/table"
@@ -21,6 +22,7 @@ type DailyReport struct {
 	timeSpentInMinutesToday int
 	timeSpentInMinutes      int
 	csvRecords              []csv.Record
+	summaries               map[string]string
 }
 
 // NewDailyReport generates DailyReport by extracting today's data from all records in the provided data CSV.
@@ -39,13 +41,27 @@ func NewDailyReport(csvRecords []csv.Record, showAll bool) *DailyReport {
 	return dr
 }
 
+// WithSummaries shows summaries of the tickets, e.g. from the issue cache
+func (r *DailyReport) WithSummaries(summaries map[string]string) *DailyReport {
+	r.summaries = summaries
+	return r
+}
+
 // Print displays DailyReport to stdout in a form of formatted a table with a header, rows for individual logs, and a summary row.
 // It also displays if the log item has been pushed to the Jira server, and number of pushed records out of all today's logs
 func (r *DailyReport) Print() {
 	log.Println(r)
 	t := table.NewWriter()
 	t.SetOutputMirror(os.Stdout)
-	t.AppendHeader(table.Row{"started at", "ticket", "time tracked (today)", "comment", "pushed to Jira? (today)"})
+	header := table.Row{"started at", "ticket", "time tracked (today)", "comment", "pushed to Jira? (today)"}
+	if r.summaries != nil {
+		header = slices.Insert(header, 2, any("summary"))
+	}
+	if r.showAll {
+		// records are addressed by their numbers in edit and rm commands
+		header = slices.Insert(header, 0, any("#"))
+	}
+	t.AppendHeader(header)
 	var totalPushed int
 	for _, rec := range r.csvRecords {
 		if !r.showAll && !csv.TodaysRowsCsvRecordPredicate(rec) {
@@ -58,21 +74,51 @@ func (r *DailyReport) Print() {
 		} else {
 			isPushed = "N"
 		}
-		t.AppendRow(table.Row{rec.StartedTs, rec.Ticket, rec.TimeSpent, rec.Comment, isPushed})
+		row := table.Row{rec.StartedTs, rec.Ticket, rec.TimeSpent, rec.Comment, isPushed}
+		if r.summaries != nil {
+			row = slices.Insert(row, 2, any(summary(r.summaries, rec.Ticket)))
+		}
+		if r.showAll {
+			row = slices.Insert(row, 0, any(rec.GetIdx()+1))
+		}
+		t.AppendRow(row)
 	}
 
-	t.AppendFooter(table.Row{
-		"today: " + time.Now().Format(config.DefaultDatePattern),
+	now := time.Now()
+	today := duration.ToString(r.timeSpentInMinutesToday)
+	if target := config.WorkSchedule().Target(now); target > 0 {
+		today = fmt.Sprintf("%v / %v", today, duration.ToString(int(target/time.Minute)))
+	}
+	footer := table.Row{
+		"today: " + now.Format(config.DefaultDatePattern),
 		"", //ticket
 		fmt.Sprintf("%v (%v)",
-			duration.ToString(r.timeSpentInMinutes),
-			duration.ToString(r.timeSpentInMinutesToday)), //time tracked
+			duration.ToString(r.timeSpentInMinutes), today), //time tracked
 		"", //comment
 		fmt.Sprintf("%v/%v", totalPushed, r.tasksToday), //pushed to jira
-	})
+	}
+	if r.summaries != nil {
+		footer = slices.Insert(footer, 2, any("")) //summary
+	}
+	if r.showAll {
+		footer = slices.Insert(footer, 0, any("")) //#
+	}
+	t.AppendFooter(footer)
 	t.Render()
 }
 
+// summaryWidth is a maximum length of a summary in reports
+const summaryWidth = 40
+
+// summary returns the ticket's summary, shortened to summaryWidth
+func summary(summaries map[string]string, ticket string) string {
+	s := []rune(summaries[ticket])
+	if len(s) > summaryWidth {
+		return string(s[:summaryWidth-1]) + "…"
+	}
+	return string(s)
+}
+
 func addTimeSpent(r csv.Record, timeSpentInMinutes int) int {
 	tsm := duration.ToMinutes(r.TimeSpent)
 	return timeSpentInMinutes + tsm
@@ -0,0 +1,75 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jedib0t/go-pretty/table"
+
+	"github.com/philgal/jtl/internal/csv"
+	"github.com/philgal/jtl/internal/reconcile"
+)
+
+// DiffReport displays a difference between the data file and Jira
+type DiffReport struct {
+	result reconcile.Result
+}
+
+// NewDiffReport generates DiffReport from the reconciliation result
+func NewDiffReport(result reconcile.Result) *DiffReport {
+	return &DiffReport{result: result}
+}
+
+// Print displays DiffReport to stdout in a form of formatted table with a row per differing record, grouped by the kind of difference
+func (r *DiffReport) Print() {
+	r.PrintTo(os.Stdout)
+}
+
+// PrintTo displays DiffReport to the writer, see Print
+func (r *DiffReport) PrintTo(w io.Writer) {
+	if r.result.IsEmpty() {
+		fmt.Fprintln(w, "Data file is in sync with Jira")
+		return
+	}
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	t.AppendHeader(table.Row{"difference", "id", "started at", "ticket", "time spent", "visibility", "comment"})
+	appendRecords := func(kind string, recs []csv.Record) {
+		for _, rec := range recs {
+			t.AppendRow(table.Row{kind, rec.ID, rec.StartedTs, rec.Ticket, rec.TimeSpent, rec.Visibility, rec.Comment})
+		}
+	}
+	appendRecords("missing in Jira", r.result.MissingRemotely)
+	for _, m := range r.result.Modified {
+		row := table.Row{"changed in Jira", m.Record.ID, m.Record.StartedTs, m.Record.Ticket, m.Record.TimeSpent, m.Record.Visibility, m.Record.Comment}
+		for _, c := range m.Changes {
+			switch c.Field {
+			case "started":
+				row[2] = fmt.Sprintf("%v -> %v", c.Local, c.Remote)
+			case "timeSpent":
+				row[4] = fmt.Sprintf("%v -> %v", c.Local, c.Remote)
+			case "visibility":
+				row[5] = fmt.Sprintf("%v -> %v", visibilityOf(c.Local), visibilityOf(c.Remote))
+			case "comment":
+				row[6] = fmt.Sprintf("%q -> %q", c.Local, c.Remote)
+			}
+		}
+		t.AppendRow(row)
+	}
+	appendRecords("not pushed", r.result.Unpushed)
+	appendRecords("only in Jira", r.result.RemoteOnly)
+	t.AppendFooter(table.Row{
+		fmt.Sprintf("missing: %v, changed: %v, not pushed: %v, only in Jira: %v",
+			len(r.result.MissingRemotely), len(r.result.Modified), len(r.result.Unpushed), len(r.result.RemoteOnly)),
+	})
+	t.Render()
+}
+
+// visibilityOf returns the worklog's visibility restriction, "all" if it's visible to all
+func visibilityOf(v string) string {
+	if v == "" {
+		return "all"
+	}
+	return v
+}
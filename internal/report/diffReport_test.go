@@ -0,0 +1,40 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/philgal/jtl/internal/csv"
+	"github.com/philgal/jtl/internal/reconcile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffReport(t *testing.T) {
+	pushed := csv.Record{ID: "1", StartedTs: "14 Apr 2020 11:30", Comment: "Review", TimeSpent: "1h", Ticket: "TICKET-1"}
+
+	t.Run("Should show changed visibility", func(t *testing.T) {
+		var out bytes.Buffer
+		result := reconcile.Result{Modified: []reconcile.Modified{{
+			Record:  pushed,
+			Changes: reconcile.Compare(pushed, csv.Record{StartedTs: pushed.StartedTs, Comment: pushed.Comment, TimeSpent: pushed.TimeSpent, Visibility: "role:Developers"}),
+		}}}
+
+		NewDiffReport(result).PrintTo(&out)
+
+		assert.Contains(t, out.String(), "all -> role:Developers")
+		assert.Contains(t, out.String(), "CHANGED: 1")
+	})
+
+	t.Run("Should show changed time spent and comment", func(t *testing.T) {
+		var out bytes.Buffer
+		result := reconcile.Result{Modified: []reconcile.Modified{{
+			Record:  pushed,
+			Changes: reconcile.Compare(pushed, csv.Record{StartedTs: pushed.StartedTs, Comment: "Code review", TimeSpent: "2h"}),
+		}}}
+
+		NewDiffReport(result).PrintTo(&out)
+
+		assert.Contains(t, out.String(), "1h -> 2h")
+		assert.Contains(t, out.String(), `"Review" -> "Code review"`)
+	})
+}
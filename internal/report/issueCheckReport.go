@@ -0,0 +1,37 @@
+package report
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jedib0t/go-pretty/table"
+
+	"github.com/philgal/jtl/internal/jira"
+)
+
+// IssueCheckReport displays the issues records are about to be pushed to, with problems preventing it
+type IssueCheckReport struct {
+	checks   []jira.IssueCheck
+	worklogs map[string]int
+}
+
+// NewIssueCheckReport generates IssueCheckReport from the checks and numbers of worklogs per issue
+func NewIssueCheckReport(checks []jira.IssueCheck, worklogs map[string]int) *IssueCheckReport {
+	return &IssueCheckReport{checks: checks, worklogs: worklogs}
+}
+
+// Print displays IssueCheckReport to stdout in a form of formatted table with a row per issue
+func (r *IssueCheckReport) Print() {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"ticket", "summary", "status", "worklogs", "problem"})
+	invalid := 0
+	for _, c := range r.checks {
+		if !c.IsValid() {
+			invalid++
+		}
+		t.AppendRow(table.Row{c.Key, c.Summary, c.Status, r.worklogs[c.Key], c.Problem})
+	}
+	t.AppendFooter(table.Row{fmt.Sprintf("tickets: %v", len(r.checks)), "", "", "", fmt.Sprintf("invalid: %v", invalid)})
+	t.Render()
+}
@@ -3,6 +3,7 @@ package report
 import (
 	"fmt"
 	"os"
+	"slices"
 	"time"
 
 	"github.com/jedib0t/go-pretty/table"
@@ -19,17 +20,35 @@ type MonthlyReport struct {
 	totalMinutes       int
 	totalTasks         int
 	totalTasksPushed   int
+	// targetMinutes are working hours of the month in the schedule
+	targetMinutes int
+	// tickets are totals per ticket, shown with summaries
+	tickets   []ticketTotal
+	summaries map[string]string
+}
+
+type ticketTotal struct {
+	ticket       string
+	totalTasks   int
+	totalMinutes int
 }
 
 // NewMonthlyReport generates MonthlyReport by extracting weekly-grouped items from all records in the provided data CSV
 func NewMonthlyReport(csvRecords []csv.Record) *MonthlyReport {
 	mr := &MonthlyReport{}
+	schedule := config.WorkSchedule()
 	//Create weekly reports
 	//Iterate by CSV rows and append new weekly reports based on weekStart/weekEnd dates deducted from the individual records
 	for _, r := range csvRecords {
 		startedTs, _ := time.ParseInLocation(config.DefaultDateTimePattern, r.StartedTs, time.Local)
 		weekStart, weekEnd := weekBoundaries(startedTs)
 		wr := mr.weeklyReportByWeekStart(weekStart)
+		if wr.weekStart == "" {
+			wr.targetMinutes = targetMinutes(schedule, weekDays(startedTs), startedTs.Month())
+			if mr.targetMinutes == 0 {
+				mr.targetMinutes = targetMinutes(schedule, monthDays(startedTs), startedTs.Month())
+			}
+		}
 		wr.weekStart = weekStart
 		wr.weekEnd = weekEnd
 		wr.totalTasks++
@@ -37,6 +56,7 @@ func NewMonthlyReport(csvRecords []csv.Record) *MonthlyReport {
 			wr.pushedTasks++
 		}
 		wr.totalMinutes += duration.ToMinutes(r.TimeSpent)
+		mr.addTicketTotal(r)
 	}
 	//Summarize totals from weekly reports
 	for _, wr := range mr.weeklyReports {
@@ -47,24 +67,56 @@ func NewMonthlyReport(csvRecords []csv.Record) *MonthlyReport {
 	return mr
 }
 
+func (r *MonthlyReport) addTicketTotal(rec csv.Record) {
+	i := slices.IndexFunc(r.tickets, func(tt ticketTotal) bool { return tt.ticket == rec.Ticket })
+	if i < 0 {
+		r.tickets = append(r.tickets, ticketTotal{ticket: rec.Ticket})
+		i = len(r.tickets) - 1
+	}
+	r.tickets[i].totalTasks++
+	r.tickets[i].totalMinutes += duration.ToMinutes(rec.TimeSpent)
+}
+
+// WithSummaries shows totals per ticket with the tickets' summaries, e.g. from the issue cache
+func (r *MonthlyReport) WithSummaries(summaries map[string]string) *MonthlyReport {
+	r.summaries = summaries
+	return r
+}
+
 // Print displays a MonthlyReport to stdout in a form of formatted a table with a header, rows for weekly summary, and a total monthly summary row.
 func (r *MonthlyReport) Print() {
 	t := table.NewWriter()
 	t.SetOutputMirror(os.Stdout)
-	t.AppendHeader(table.Row{"Week", "Total tasks (pushed)", "Total time"})
+	t.AppendHeader(table.Row{"Week", "Total tasks (pushed)", "Total time", "Target"})
 	for _, wr := range r.weeklyReports {
 		t.AppendRow([]interface{}{
 			fmt.Sprintf("%v - %v", wr.weekStart, wr.weekEnd),
 			fmt.Sprintf("%v (%v)", wr.totalTasks, wr.pushedTasks),
 			duration.ToString(wr.totalMinutes),
+			duration.ToString(wr.targetMinutes),
 		})
 	}
 	t.AppendFooter(table.Row{
 		"Total for: " + config.GetCurrentDataFileName(),
 		fmt.Sprintf("%v (%v)", r.totalTasks, r.totalTasksPushed),
 		duration.ToString(r.totalMinutes),
+		duration.ToString(r.targetMinutes),
 	})
 	t.Render()
+	if r.summaries != nil {
+		r.printTickets()
+	}
+}
+
+// printTickets displays totals per ticket with summaries
+func (r *MonthlyReport) printTickets() {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"Ticket", "Summary", "Total tasks", "Total time"})
+	for _, tt := range r.tickets {
+		t.AppendRow(table.Row{tt.ticket, summary(r.summaries, tt.ticket), tt.totalTasks, duration.ToString(tt.totalMinutes)})
+	}
+	t.Render()
 }
 
 func (r *MonthlyReport) weeklyReportByWeekStart(date string) *WeeklyReport {
@@ -82,7 +134,37 @@ func (r *MonthlyReport) weeklyReportByWeekStart(date string) *WeeklyReport {
 }
 
 func weekBoundaries(t time.Time) (string, string) {
-	weekStart := t.AddDate(0, 0, int(time.Monday-t.Weekday()))
+	weekStart := weekDays(t)[0]
 	weekEnd := weekStart.AddDate(0, 0, 4)
 	return weekStart.Format(config.DefaultDatePattern), weekEnd.Format(config.DefaultDatePattern)
 }
+
+// weekDays returns days of the week of the time, from Monday to Sunday. Sunday's week is the following one.
+func weekDays(t time.Time) []time.Time {
+	weekStart := t.AddDate(0, 0, int(time.Monday-t.Weekday()))
+	days := make([]time.Time, 7)
+	for i := range days {
+		days[i] = weekStart.AddDate(0, 0, i)
+	}
+	return days
+}
+
+// monthDays returns days of the month of the time
+func monthDays(t time.Time) []time.Time {
+	var days []time.Time
+	for day := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location()); day.Month() == t.Month(); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+	return days
+}
+
+// targetMinutes sums working hours of the days of the month in the schedule
+func targetMinutes(schedule config.Schedule, days []time.Time, month time.Month) int {
+	var target time.Duration
+	for _, day := range days {
+		if day.Month() == month {
+			target += schedule.Target(day)
+		}
+	}
+	return int(target / time.Minute)
+}
@@ -16,7 +16,9 @@ package report
 
 import (
 	"testing"
+	"time"
 
+	"github.com/philgal/jtl/internal/config"
 	"github.com/philgal/jtl/internal/csv"
 	"github.com/philgal/jtl/internal/duration"
 	"github.com/stretchr/testify/assert"
@@ -67,20 +69,24 @@ func Test_NewMonthlyReport(t *testing.T) {
 						weekStart: "13 Apr 2020",
 						weekEnd:   "17 Apr 2020",
 						//12h35m = 3*60 + 8*60 + 1*60 + 35 = 755
-						totalMinutes: 3*60 + 8*60 + 1*60 + 35,
-						totalTasks:   2,
+						totalMinutes:  3*60 + 8*60 + 1*60 + 35,
+						totalTasks:    2,
+						targetMinutes: 5 * 8 * 60,
 					},
 					{
 						weekStart: "20 Apr 2020",
 						weekEnd:   "24 Apr 2020",
 						//20m
-						totalMinutes: 20,
-						totalTasks:   1,
+						totalMinutes:  20,
+						totalTasks:    1,
+						targetMinutes: 5 * 8 * 60,
 					},
 				},
 				totalMinutes:     775, //755 + 20
 				totalTasks:       3,
 				totalTasksPushed: 0,
+				// 22 working days in Apr 2020
+				targetMinutes: 22 * 8 * 60,
 			},
 		},
 	}
@@ -91,6 +97,7 @@ func Test_NewMonthlyReport(t *testing.T) {
 			assert.Equal(tt.want.totalMinutes, got.totalMinutes)
 			assert.Equal(tt.want.totalTasks, got.totalTasks)
 			assert.Equal(tt.want.totalTasksPushed, got.totalTasksPushed)
+			assert.Equal(tt.want.targetMinutes, got.targetMinutes)
 			assert.Equal(2, len(got.weeklyReports))
 			for idx, wr := range tt.want.weeklyReports {
 				assert.Exactly(wr, got.weeklyReports[idx])
@@ -98,3 +105,23 @@ func Test_NewMonthlyReport(t *testing.T) {
 		})
 	}
 }
+
+func Test_weekBoundaries(t *testing.T) {
+	t.Run("Should put Sunday into the following week", func(t *testing.T) {
+		weekStart, weekEnd := weekBoundaries(time.Date(2020, 4, 19, 12, 0, 0, 0, time.Local))
+		assert.Equal(t, "20 Apr 2020", weekStart)
+		assert.Equal(t, "24 Apr 2020", weekEnd)
+	})
+}
+
+func Test_targetMinutes(t *testing.T) {
+	schedule := config.DefaultSchedule()
+	schedule.Hours[time.Saturday] = 4 * time.Hour
+	t.Run("Should sum working hours of the week", func(t *testing.T) {
+		assert.Equal(t, 5*8*60+4*60, targetMinutes(schedule, weekDays(time.Date(2020, 4, 15, 12, 0, 0, 0, time.Local)), time.April))
+	})
+	t.Run("Should sum working hours of the week within the month", func(t *testing.T) {
+		// week 27 Apr - 3 May 2020
+		assert.Equal(t, 4*8*60, targetMinutes(schedule, weekDays(time.Date(2020, 4, 28, 12, 0, 0, 0, time.Local)), time.April))
+	})
+}
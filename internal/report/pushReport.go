@@ -0,0 +1,95 @@
+package report
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/jedib0t/go-pretty/table"
+
+	"github.com/philgal/jtl/internal/config"
+	"github.com/philgal/jtl/internal/model"
+)
+
+// actions are human-readable names of push request methods
+var actions = map[string]string{
+	http.MethodPost:   "create",
+	http.MethodPut:    "update",
+	http.MethodDelete: "delete",
+}
+
+// PushReport displays an outcome of every record attempted to be pushed to Jira
+type PushReport struct {
+	responses []model.JiraResponse
+	pushed    int
+	attempts  int
+	// withTarget is true if responses come from more than one target
+	withTarget bool
+}
+
+// NewPushReport generates PushReport from the responses collected during push
+func NewPushReport(responses []model.JiraResponse) *PushReport {
+	pr := &PushReport{responses: responses}
+	for _, r := range responses {
+		pr.withTarget = pr.withTarget || r.Target != responses[0].Target
+		pr.attempts += r.Attempts
+		if r.IsSuccess {
+			pr.pushed++
+		}
+	}
+	return pr
+}
+
+// HasFailures returns true if at least one record has not been pushed
+func (r *PushReport) HasFailures() bool {
+	return r.pushed < len(r.responses)
+}
+
+// Print displays PushReport to stdout in a form of formatted table with a row per attempted record and a summary row.
+func (r *PushReport) Print() {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	header := table.Row{"action", "ticket", "started at", "time spent", "status", "attempts", "errors"}
+	if r.withTarget {
+		header = append(table.Row{"target"}, header...)
+	}
+	t.AppendHeader(header)
+	for _, res := range r.responses {
+		status := "-"
+		if res.StatusCode != 0 {
+			status = fmt.Sprint(res.StatusCode)
+		}
+		action := actions[res.Method]
+		if res.IsAdopted {
+			action = "adopt existing"
+		}
+		row := table.Row{action, res.Ticket, formatStarted(res.Started), res.Timespent, status, res.Attempts, res.FailureReason()}
+		if r.withTarget {
+			row = append(table.Row{res.Target}, row...)
+		}
+		t.AppendRow(row)
+	}
+	footer := table.Row{
+		fmt.Sprintf("pushed: %v/%v", r.pushed, len(r.responses)),
+		"", //ticket
+		"", //started at
+		"", //time spent
+		"", //status
+		r.attempts,
+		fmt.Sprintf("failed: %v", len(r.responses)-r.pushed),
+	}
+	if r.withTarget {
+		footer = append(table.Row{""}, footer...)
+	}
+	t.AppendFooter(footer)
+	t.Render()
+}
+
+// formatStarted displays Jira's ISO timestamp in the data file format, leaving local values as they are
+func formatStarted(started string) string {
+	if t, err := time.Parse(config.JiraDateTimePattern, started); err == nil {
+		return t.Local().Format(config.DefaultDateTimePattern)
+	}
+	return started
+}
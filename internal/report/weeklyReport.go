@@ -7,4 +7,6 @@ type WeeklyReport struct {
 	totalTasks   int //including aliased, len(records)
 	pushedTasks  int //tasks with ids
 	totalMinutes int
+	//working hours of the week's days of the month in the schedule
+	targetMinutes int
 }
@@ -0,0 +1,59 @@
+package rest
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimiter spaces out requests evenly so that no more than the given number of requests per second are sent.
+// A nil RateLimiter doesn't limit anything.
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// NewRateLimiter creates a RateLimiter for requestsPerSecond. Returns nil (no limit) if requestsPerSecond is not positive.
+func NewRateLimiter(requestsPerSecond float64) *RateLimiter {
+	if requestsPerSecond <= 0 {
+		return nil
+	}
+	return &RateLimiter{interval: time.Duration(float64(time.Second) / requestsPerSecond)}
+}
+
+// Wait blocks until the next request is allowed to be sent
+func (l *RateLimiter) Wait() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+	if wait > 0 {
+		sleep(wait)
+	}
+}
+
+type rateLimitedClient struct {
+	client  Client
+	limiter *RateLimiter
+}
+
+// WithRateLimit wraps the client, so that every request, including retried ones, waits for the limiter
+func WithRateLimit(client Client, limiter *RateLimiter) Client {
+	if limiter == nil {
+		return client
+	}
+	return &rateLimitedClient{client: client, limiter: limiter}
+}
+
+func (c *rateLimitedClient) Do(req *http.Request) (*http.Response, error) {
+	c.limiter.Wait()
+	return c.client.Do(req)
+}
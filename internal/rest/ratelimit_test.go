@@ -0,0 +1,33 @@
+package rest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter_Wait(t *testing.T) {
+	t.Run("Should space out requests by interval", func(t *testing.T) {
+		slept := recordSleeps(t)
+		limiter := NewRateLimiter(2)
+
+		for range 3 {
+			limiter.Wait()
+		}
+
+		assert.Len(t, *slept, 2)
+		assert.InDelta(t, float64(500*time.Millisecond), float64((*slept)[0]), float64(50*time.Millisecond))
+		assert.InDelta(t, float64(time.Second), float64((*slept)[1]), float64(50*time.Millisecond))
+	})
+
+	t.Run("Should not limit when rate is not set", func(t *testing.T) {
+		slept := recordSleeps(t)
+		limiter := NewRateLimiter(0)
+
+		limiter.Wait()
+
+		assert.Nil(t, limiter)
+		assert.Empty(t, *slept)
+	})
+}
@@ -0,0 +1,138 @@
+package rest
+
+import (
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultMaxAttempts = 3
+	DefaultBaseDelay   = 500 * time.Millisecond
+	DefaultMaxDelay    = 30 * time.Second
+)
+
+// sleep is a package-level hook, so tests don't have to wait for real backoff delays
+var sleep = time.Sleep
+
+// RetryPolicy describes how many times and how often a failed request is retried.
+// Zero values fall back to the package defaults.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DoWithRetry sends req with the client and retries it according to the policy.
+// Requests are retried on 429 and 503 (the server refused to process them, honoring Retry-After),
+// and, for idempotent requests only, on transport errors, 502 and 504.
+// It returns the last response or error along with the number of attempts made.
+func DoWithRetry(client Client, req *http.Request, policy RetryPolicy) (*http.Response, int, error) {
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, attempt - 1, err
+			}
+			req.Body = body
+		}
+		res, err := client.Do(req)
+		if attempt >= policy.maxAttempts() || !shouldRetry(req, res, err) {
+			return res, attempt, err
+		}
+		delay, ok := policy.delay(attempt, res)
+		if !ok {
+			// server asked to wait longer than we are willing to, give up with its response
+			return res, attempt, err
+		}
+		if err != nil {
+			log.Printf("Attempt %v of %v %v failed: %v, retrying in %v\n", attempt, req.Method, req.URL, err, delay)
+		} else {
+			log.Printf("Attempt %v of %v %v responded %v, retrying in %v\n", attempt, req.Method, req.URL, res.Status, delay)
+			io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+		}
+		sleep(delay)
+	}
+}
+
+func shouldRetry(req *http.Request, res *http.Response, err error) bool {
+	if err != nil {
+		return isIdempotent(req)
+	}
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return isIdempotent(req)
+	default:
+		return false
+	}
+}
+
+// isIdempotent reports whether a request can be safely sent again after its outcome is unknown
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	_, hasKey := req.Header["Idempotency-Key"]
+	return hasKey
+}
+
+func (p RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts <= 0 {
+		return DefaultMaxAttempts
+	}
+	return p.MaxAttempts
+}
+
+func (p RetryPolicy) baseDelay() time.Duration {
+	if p.BaseDelay <= 0 {
+		return DefaultBaseDelay
+	}
+	return p.BaseDelay
+}
+
+func (p RetryPolicy) maxDelay() time.Duration {
+	if p.MaxDelay <= 0 {
+		return DefaultMaxDelay
+	}
+	return p.MaxDelay
+}
+
+// delay returns how long to wait before the next attempt: Retry-After if the server sent one,
+// exponential backoff with full jitter otherwise. ok is false if Retry-After exceeds MaxDelay.
+func (p RetryPolicy) delay(attempt int, res *http.Response) (d time.Duration, ok bool) {
+	if res != nil {
+		if retryAfter, found := parseRetryAfter(res.Header.Get("Retry-After")); found {
+			return retryAfter, retryAfter <= p.maxDelay()
+		}
+	}
+	backoff := p.baseDelay() << (attempt - 1)
+	if backoff <= 0 || backoff > p.maxDelay() {
+		backoff = p.maxDelay()
+	}
+	return rand.N(backoff) + 1, true
+}
+
+// parseRetryAfter parses Retry-After header value, which is either delay in seconds or an HTTP date
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
@@ -0,0 +1,101 @@
+package rest
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type scriptedClient struct {
+	responses []*http.Response
+	errs      []error
+	bodies    []string
+	calls     int
+}
+
+func (c *scriptedClient) Do(req *http.Request) (*http.Response, error) {
+	i := c.calls
+	c.calls++
+	if req.Body != nil {
+		b, _ := io.ReadAll(req.Body)
+		c.bodies = append(c.bodies, string(b))
+	}
+	return c.responses[i], c.errs[i]
+}
+
+func response(status int, headers ...string) *http.Response {
+	res := &http.Response{StatusCode: status, Status: http.StatusText(status), Header: http.Header{}, Body: io.NopCloser(strings.NewReader(""))}
+	for i := 0; i+1 < len(headers); i += 2 {
+		res.Header.Set(headers[i], headers[i+1])
+	}
+	return res
+}
+
+func recordSleeps(t *testing.T) *[]time.Duration {
+	var slept []time.Duration
+	sleep = func(d time.Duration) { slept = append(slept, d) }
+	t.Cleanup(func() { sleep = time.Sleep })
+	return &slept
+}
+
+func TestDoWithRetry(t *testing.T) {
+	t.Run("Should honor Retry-After and resend the body", func(t *testing.T) {
+		slept := recordSleeps(t)
+		client := &scriptedClient{
+			responses: []*http.Response{response(429, "Retry-After", "2"), response(201)},
+			errs:      []error{nil, nil},
+		}
+		req, _ := http.NewRequest(http.MethodPost, "http://jira/worklog", bytes.NewBufferString(`{"a":1}`))
+
+		res, attempts, err := DoWithRetry(client, req, RetryPolicy{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 201, res.StatusCode)
+		assert.Equal(t, 2, attempts)
+		assert.Equal(t, []time.Duration{2 * time.Second}, *slept)
+		assert.Equal(t, []string{`{"a":1}`, `{"a":1}`}, client.bodies)
+	})
+
+	t.Run("Should not retry POST on transport errors", func(t *testing.T) {
+		recordSleeps(t)
+		client := &scriptedClient{responses: []*http.Response{nil}, errs: []error{errors.New("connection reset")}}
+		req, _ := http.NewRequest(http.MethodPost, "http://jira/worklog", nil)
+
+		_, attempts, err := DoWithRetry(client, req, RetryPolicy{})
+
+		assert.Error(t, err)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("Should retry GET on transport errors up to max attempts", func(t *testing.T) {
+		slept := recordSleeps(t)
+		failure := errors.New("connection reset")
+		client := &scriptedClient{responses: make([]*http.Response, 3), errs: []error{failure, failure, failure}}
+		req, _ := http.NewRequest(http.MethodGet, "http://jira/worklog", nil)
+
+		_, attempts, err := DoWithRetry(client, req, RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: 10 * time.Second})
+
+		assert.ErrorIs(t, err, failure)
+		assert.Equal(t, 3, attempts)
+		assert.Len(t, *slept, 2)
+		assert.LessOrEqual(t, (*slept)[1], 2*time.Second)
+	})
+
+	t.Run("Should give up when Retry-After exceeds max delay", func(t *testing.T) {
+		slept := recordSleeps(t)
+		client := &scriptedClient{responses: []*http.Response{response(503, "Retry-After", "3600")}, errs: []error{nil}}
+		req, _ := http.NewRequest(http.MethodGet, "http://jira/worklog", nil)
+
+		res, attempts, _ := DoWithRetry(client, req, RetryPolicy{})
+
+		assert.Equal(t, 503, res.StatusCode)
+		assert.Equal(t, 1, attempts)
+		assert.Empty(t, *slept)
+	})
+}
@@ -0,0 +1,130 @@
+package rest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(server.Close)
+	caFile := path.Join(t.TempDir(), "ca.pem")
+	os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0644)
+
+	t.Run("Should use default timeout and reject unknown CA", func(t *testing.T) {
+		client, err := NewHTTPClient(TransportConfig{})
+		assert.NoError(t, err)
+		assert.Equal(t, DefaultTimeout, client.Timeout)
+
+		_, err = client.Get(server.URL)
+
+		assert.ErrorContains(t, err, "certificate")
+	})
+
+	t.Run("Should trust certificates of CA file", func(t *testing.T) {
+		client, err := NewHTTPClient(TransportConfig{CAFile: caFile, Timeout: time.Second})
+		assert.NoError(t, err)
+		assert.Equal(t, time.Second, client.Timeout)
+
+		resp, err := client.Get(server.URL)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("Should skip verification if insecure", func(t *testing.T) {
+		client, _ := NewHTTPClient(TransportConfig{InsecureSkipVerify: true})
+
+		_, err := client.Get(server.URL)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should send requests through proxy", func(t *testing.T) {
+		var proxied string
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proxied = r.URL.String()
+		}))
+		t.Cleanup(proxy.Close)
+		client, err := NewHTTPClient(TransportConfig{Proxy: proxy.URL})
+		assert.NoError(t, err)
+
+		_, err = client.Get("http://jira.example.com/rest/api/2/myself")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "http://jira.example.com/rest/api/2/myself", proxied)
+	})
+
+	t.Run("Should fail on invalid config", func(t *testing.T) {
+		_, err := NewHTTPClient(TransportConfig{Proxy: "not a url"})
+		assert.ErrorContains(t, err, "invalid proxy URL")
+
+		_, err = NewHTTPClient(TransportConfig{CAFile: path.Join(t.TempDir(), "missing.pem")})
+		assert.ErrorContains(t, err, "cannot read CA file")
+
+		_, err = NewHTTPClient(TransportConfig{ClientCert: caFile})
+		assert.ErrorContains(t, err, "both client certificate and key must be set")
+
+		_, err = NewHTTPClient(TransportConfig{ClientCert: caFile, ClientKey: caFile})
+		assert.ErrorContains(t, err, "invalid client certificate")
+	})
+}
+
+func TestClientCertificate(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	dir := t.TempDir()
+	certFile, keyFile := path.Join(dir, "client.pem"), path.Join(dir, "client.key")
+	certPEM, keyPEM := newClientCertificate(t)
+	os.WriteFile(certFile, certPEM, 0644)
+	os.WriteFile(keyFile, keyPEM, 0600)
+
+	t.Run("Should present client certificate for mutual TLS", func(t *testing.T) {
+		client, err := NewHTTPClient(TransportConfig{ClientCert: certFile, ClientKey: keyFile, InsecureSkipVerify: true})
+		assert.NoError(t, err)
+
+		_, err = client.Get(server.URL)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should be refused without client certificate", func(t *testing.T) {
+		client, _ := NewHTTPClient(TransportConfig{InsecureSkipVerify: true})
+
+		_, err := client.Get(server.URL)
+
+		assert.Error(t, err)
+	})
+}
+
+// newClientCertificate generates a self-signed client certificate and its key in PEM
+func newClientCertificate(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
@@ -0,0 +1,288 @@
+package target
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/philgal/jtl/internal/duration"
+	"github.com/philgal/jtl/internal/jsonpath"
+	"github.com/philgal/jtl/internal/model"
+	"github.com/philgal/jtl/internal/rest"
+)
+
+// Request is a templated request of HTTP target. URL, header values and body are text/template templates, rendered with RecordData.
+type Request struct {
+	Method  string
+	URL     string
+	Headers map[string]string
+	Body    string
+	// ID is a JSONPath-like selector of the remote ID in the response, e.g. $.data.id
+	ID string
+}
+
+// RecordData is the data HTTP target templates are rendered with.
+// Besides text/template builtins, e.g. urlquery, templates can use json, encoding a value as JSON, and env, reading an environment variable.
+type RecordData struct {
+	// ID is the remote ID of the record, set for updates and deletes
+	ID         string
+	Ticket     string
+	Comment    string
+	TimeSpent  string
+	Minutes    int
+	Hours      float64
+	Started    time.Time
+	Visibility string
+}
+
+// HTTP pushes records to a custom HTTP API, e.g. a billing system, rendering requests from templates.
+// Records are only updated and deleted if the corresponding requests are configured.
+type HTTP struct {
+	Name   string
+	Client rest.Client
+	Retry  rest.RetryPolicy
+
+	create, update, remove *httpRequest
+}
+
+type httpRequest struct {
+	method  string
+	url     *template.Template
+	headers map[string]*template.Template
+	body    *template.Template
+	id      string
+}
+
+// HTTPStatusError is an unsuccessful response of HTTP target
+type HTTPStatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPStatusError) Error() string {
+	body := strings.TrimSpace(e.Body)
+	if len(body) > 200 {
+		body = body[:200] + "..."
+	}
+	if body == "" {
+		return fmt.Sprintf("server responded %v", e.StatusCode)
+	}
+	return fmt.Sprintf("server responded %v: %v", e.StatusCode, body)
+}
+
+// NewHTTP creates HTTP target, parsing its templates. Create request is required.
+// Update and delete requests, if their URL is set, inherit headers of create request, update also inherits its body.
+func NewHTTP(name string, create, update, remove Request, client rest.Client) (*HTTP, error) {
+	if create.URL == "" {
+		return nil, fmt.Errorf("target %v: url is required", name)
+	}
+	t := &HTTP{Name: name, Client: client}
+	var err error
+	if t.create, err = parseRequest(name, create, http.MethodPost); err != nil {
+		return nil, err
+	}
+	if update.URL != "" {
+		if update.Headers == nil {
+			update.Headers = create.Headers
+		}
+		if update.Body == "" {
+			update.Body = create.Body
+		}
+		if t.update, err = parseRequest(name, update, http.MethodPut); err != nil {
+			return nil, err
+		}
+	}
+	if remove.URL != "" {
+		if remove.Headers == nil {
+			remove.Headers = create.Headers
+		}
+		if t.remove, err = parseRequest(name, remove, http.MethodDelete); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"env": os.Getenv,
+}
+
+func parseRequest(name string, r Request, defaultMethod string) (*httpRequest, error) {
+	parse := func(field, text string) (*template.Template, error) {
+		tmpl, err := template.New(name + "." + field).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("target %v: %w", name, err)
+		}
+		return tmpl, nil
+	}
+	req := &httpRequest{method: strings.ToUpper(strings.TrimSpace(r.Method)), headers: map[string]*template.Template{}, id: r.ID}
+	if req.method == "" {
+		req.method = defaultMethod
+	}
+	var err error
+	if req.url, err = parse("url", r.URL); err != nil {
+		return nil, err
+	}
+	if r.Body != "" {
+		if req.body, err = parse("body", r.Body); err != nil {
+			return nil, err
+		}
+	}
+	for header, value := range r.Headers {
+		if req.headers[header], err = parse(header, value); err != nil {
+			return nil, err
+		}
+	}
+	return req, nil
+}
+
+// Create sends create request and reads the remote ID from its response
+func (t *HTTP) Create(row model.JiraRequestRow) (*Worklog, *Response, error) {
+	return t.send(t.create, row)
+}
+
+// Update sends update request, if it's configured
+func (t *HTTP) Update(row model.JiraRequestRow) (*Worklog, *Response, error) {
+	if t.update == nil {
+		return nil, &Response{}, fmt.Errorf("target %v doesn't support updates", t.Name)
+	}
+	return t.send(t.update, row)
+}
+
+// Delete sends delete request, if it's configured
+func (t *HTTP) Delete(row model.JiraRequestRow) (*Response, error) {
+	if t.remove == nil {
+		return &Response{}, fmt.Errorf("target %v doesn't support deletes", t.Name)
+	}
+	_, resp, err := t.send(t.remove, row)
+	return resp, err
+}
+
+// Preview renders the request for the row
+func (t *HTTP) Preview(row model.JiraRequestRow) (string, string, any, error) {
+	r := t.create
+	switch row.Method {
+	case http.MethodPut:
+		r = t.update
+	case http.MethodDelete:
+		r = t.remove
+	}
+	if r == nil {
+		return row.Method, "", nil, fmt.Errorf("target %v doesn't support %v", t.Name, row.Method)
+	}
+	req, body, err := t.newRequest(r, row)
+	if err != nil {
+		return r.method, "", nil, err
+	}
+	if body == "" {
+		return req.Method, req.URL.String(), nil, nil
+	}
+	return req.Method, req.URL.String(), json.RawMessage(body), nil
+}
+
+func (t *HTTP) send(r *httpRequest, row model.JiraRequestRow) (*Worklog, *Response, error) {
+	req, _, err := t.newRequest(r, row)
+	if err != nil {
+		return nil, &Response{}, err
+	}
+	res, attempts, err := rest.DoWithRetry(t.Client, req, t.Retry)
+	resp := &Response{Attempts: attempts}
+	if err != nil {
+		return nil, resp, err
+	}
+	defer res.Body.Close()
+	resp.StatusCode = res.StatusCode
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, resp, fmt.Errorf("failed to read response body: %w", err)
+	}
+	log.Printf("Target %v responded: %v\n{%q}\n", t.Name, res.Status, body)
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, resp, &HTTPStatusError{StatusCode: res.StatusCode, Body: string(body)}
+	}
+	worklog := &Worklog{ID: row.WorklogID, Started: row.Started, TimeSpent: row.Timespent, Comment: row.Comment}
+	if r.id != "" {
+		if worklog.ID, err = jsonpath.SelectString(body, r.id); err != nil {
+			return nil, resp, fmt.Errorf("cannot read ID from response: %w", err)
+		}
+	}
+	if worklog.ID == "" && row.Method == http.MethodPost {
+		return nil, resp, fmt.Errorf("target %v: set id selector to read the created ID from response", t.Name)
+	}
+	return worklog, resp, nil
+}
+
+// newRequest renders the request for the row, returning the rendered body too
+func (t *HTTP) newRequest(r *httpRequest, row model.JiraRequestRow) (*http.Request, string, error) {
+	data, err := recordData(row)
+	if err != nil {
+		return nil, "", err
+	}
+	render := func(tmpl *template.Template) (string, error) {
+		sb := strings.Builder{}
+		if err := tmpl.Execute(&sb, data); err != nil {
+			return "", fmt.Errorf("target %v: %w", t.Name, err)
+		}
+		return sb.String(), nil
+	}
+	url, err := render(r.url)
+	if err != nil {
+		return nil, "", err
+	}
+	var body string
+	var reader io.Reader
+	if r.body != nil {
+		if body, err = render(r.body); err != nil {
+			return nil, "", err
+		}
+		reader = bytes.NewReader([]byte(body))
+	}
+	req, err := http.NewRequest(r.method, strings.TrimSpace(url), reader)
+	if err != nil {
+		return nil, "", err
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	for header, tmpl := range r.headers {
+		value, err := render(tmpl)
+		if err != nil {
+			return nil, "", err
+		}
+		req.Header.Set(header, value)
+	}
+	log.Printf("[Prepared HTTP Request] %v %v\n", req.Method, req.URL)
+	return req, body, nil
+}
+
+func recordData(row model.JiraRequestRow) (RecordData, error) {
+	data := RecordData{
+		ID:         row.WorklogID,
+		Ticket:     row.Jiraticket,
+		Comment:    row.Comment,
+		TimeSpent:  row.Timespent,
+		Minutes:    duration.ToMinutes(row.Timespent),
+		Visibility: row.Visibility,
+	}
+	data.Hours = float64(data.Minutes) / 60
+	if row.Started != "" {
+		started, err := parseStarted(row.Started)
+		if err != nil {
+			return data, err
+		}
+		data.Started = started
+	}
+	return data, nil
+}
@@ -0,0 +1,233 @@
+package target
+
+import (
+	"encoding/json"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/philgal/jtl/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHTTP(t *testing.T) {
+	create := Request{
+		URL:     "https://billing.example.com/entries",
+		Headers: map[string]string{"Authorization": "Bearer token"},
+		Body:    `{"ticket": {{json .Ticket}}}`,
+		ID:      "$.id",
+	}
+	tests := []struct {
+		name   string
+		create Request
+		update Request
+		remove Request
+		err    string
+	}{
+		{"without url", Request{}, Request{}, Request{}, "target billing: url is required"},
+		{"with invalid url template", Request{URL: "{{.Ticket"}, Request{}, Request{}, "target billing: template: billing.url"},
+		{"with invalid body template", Request{URL: "https://billing.example.com", Body: "{{end}}"}, Request{}, Request{}, "target billing: template: billing.body"},
+		{"with invalid update template", create, Request{URL: "{{"}, Request{}, "target billing: template: billing.url"},
+		{"with invalid delete template", create, Request{}, Request{URL: "{{"}, "target billing: template: billing.url"},
+	}
+	for _, tt := range tests {
+		t.Run("Should refuse target "+tt.name, func(t *testing.T) {
+			_, err := NewHTTP("billing", tt.create, tt.update, tt.remove, http.DefaultClient)
+
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+
+	t.Run("Should inherit headers and body of create request", func(t *testing.T) {
+		target, err := NewHTTP("billing", create,
+			Request{URL: "https://billing.example.com/entries/{{.ID}}"},
+			Request{URL: "https://billing.example.com/entries/{{.ID}}"}, http.DefaultClient)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.MethodPost, target.create.method)
+		assert.Equal(t, http.MethodPut, target.update.method)
+		assert.Equal(t, http.MethodDelete, target.remove.method)
+		assert.Contains(t, target.update.headers, "Authorization")
+		assert.Contains(t, target.remove.headers, "Authorization")
+		assert.NotNil(t, target.update.body)
+		assert.Nil(t, target.remove.body)
+	})
+
+	t.Run("Should keep own headers and body of update request", func(t *testing.T) {
+		target, err := NewHTTP("billing", create,
+			Request{Method: "patch", URL: "https://billing.example.com/entries/{{.ID}}", Headers: map[string]string{"X-Key": "key"}, Body: `{}`},
+			Request{}, http.DefaultClient)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.MethodPatch, target.update.method)
+		assert.Equal(t, []string{"X-Key"}, slices.Sorted(maps.Keys(target.update.headers)))
+		assert.Equal(t, "{}", target.update.body.Root.String())
+		assert.Nil(t, target.remove)
+	})
+}
+
+func TestHTTP(t *testing.T) {
+	var gotMethod, gotPath, gotAuth, gotBody string
+	status, response := http.StatusCreated, `{"data":{"id":"e-1"}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath, gotAuth = r.Method, r.URL.Path, r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.WriteHeader(status)
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	create := Request{
+		URL:     server.URL + "/entries",
+		Headers: map[string]string{"Authorization": "Bearer token"},
+		Body:    `{"ticket": {{json .Ticket}}, "minutes": {{.Minutes}}, "date": {{json (.Started.Format "2006-01-02")}}}`,
+		ID:      "$.data.id",
+	}
+	entry := Request{URL: server.URL + "/entries/{{.ID}}"}
+	row := model.JiraRequestRow{Method: http.MethodPost, Jiraticket: "SUP-1", Timespent: "1h 30m", Started: "15 Apr 2020 11:30", WorklogID: "e-1"}
+	rowOf := func(method string) model.JiraRequestRow {
+		r := row
+		r.Method = method
+		return r
+	}
+
+	t.Run("Should create worklog and read its ID", func(t *testing.T) {
+		target, _ := NewHTTP("billing", create, Request{}, Request{}, server.Client())
+
+		worklog, resp, err := target.Create(model.JiraRequestRow{Method: http.MethodPost, Jiraticket: "SUP-1", Timespent: "1h 30m", Started: "15 Apr 2020 11:30"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 201, resp.StatusCode)
+		assert.Equal(t, 1, resp.Attempts)
+		assert.Equal(t, "e-1", worklog.ID)
+		assert.Equal(t, "POST /entries", gotMethod+" "+gotPath)
+		assert.Equal(t, "Bearer token", gotAuth)
+		assert.JSONEq(t, `{"ticket":"SUP-1","minutes":90,"date":"2020-04-15"}`, gotBody)
+	})
+
+	t.Run("Should update and delete worklog with inherited headers and body", func(t *testing.T) {
+		target, _ := NewHTTP("billing", create, entry, entry, server.Client())
+		status, response = http.StatusOK, `{}`
+
+		worklog, _, err := target.Update(rowOf(http.MethodPut))
+		assert.NoError(t, err)
+		assert.Equal(t, "e-1", worklog.ID)
+		assert.Equal(t, "PUT /entries/e-1", gotMethod+" "+gotPath)
+		assert.Equal(t, "Bearer token", gotAuth)
+		assert.JSONEq(t, `{"ticket":"SUP-1","minutes":90,"date":"2020-04-15"}`, gotBody)
+
+		_, err = target.Delete(rowOf(http.MethodDelete))
+		assert.NoError(t, err)
+		assert.Equal(t, "DELETE /entries/e-1", gotMethod+" "+gotPath)
+		assert.Equal(t, "Bearer token", gotAuth)
+		assert.Empty(t, gotBody)
+	})
+
+	tests := []struct {
+		name     string
+		create   Request
+		status   int
+		response string
+		send     func(target *HTTP) (*Response, error)
+		err      string
+	}{
+		{"update if it's not configured", create, http.StatusOK, `{}`,
+			func(target *HTTP) (*Response, error) {
+				_, resp, err := target.Update(rowOf(http.MethodPut))
+				return resp, err
+			}, "target billing doesn't support updates"},
+		{"delete if it's not configured", create, http.StatusOK, `{}`,
+			func(target *HTTP) (*Response, error) { return target.Delete(rowOf(http.MethodDelete)) },
+			"target billing doesn't support deletes"},
+		{"create without id selector", Request{URL: create.URL}, http.StatusCreated, `{"data":{"id":"e-1"}}`,
+			func(target *HTTP) (*Response, error) {
+				_, resp, err := target.Create(model.JiraRequestRow{Method: http.MethodPost, Started: row.Started})
+				return resp, err
+			}, "target billing: set id selector to read the created ID from response"},
+		{"create without ID in response", create, http.StatusCreated, `{"data":{}}`,
+			func(target *HTTP) (*Response, error) {
+				_, resp, err := target.Create(model.JiraRequestRow{Method: http.MethodPost, Started: row.Started})
+				return resp, err
+			}, "cannot read ID from response"},
+		{"create refused by server", create, http.StatusBadRequest, `{"error":"unknown ticket"}`,
+			func(target *HTTP) (*Response, error) {
+				_, resp, err := target.Create(rowOf(http.MethodPost))
+				return resp, err
+			}, `server responded 400: {"error":"unknown ticket"}`},
+		{"create with invalid started", create, http.StatusCreated, `{}`,
+			func(target *HTTP) (*Response, error) {
+				_, resp, err := target.Create(model.JiraRequestRow{Method: http.MethodPost, Started: "tomorrow"})
+				return resp, err
+			}, "couldn't parse date"},
+	}
+	for _, tt := range tests {
+		t.Run("Should fail "+tt.name, func(t *testing.T) {
+			target, _ := NewHTTP("billing", tt.create, Request{}, Request{}, server.Client())
+			status, response = tt.status, tt.response
+
+			resp, err := tt.send(target)
+
+			assert.ErrorContains(t, err, tt.err)
+			assert.NotNil(t, resp)
+		})
+	}
+
+	t.Run("Should return status and body of non-2xx response", func(t *testing.T) {
+		target, _ := NewHTTP("billing", create, Request{}, Request{}, server.Client())
+		status, response = http.StatusBadGateway, "  maintenance  "
+
+		_, resp, err := target.Create(rowOf(http.MethodPost))
+
+		var statusErr *HTTPStatusError
+		assert.ErrorAs(t, err, &statusErr)
+		assert.Equal(t, 502, statusErr.StatusCode)
+		assert.Equal(t, 502, resp.StatusCode)
+		assert.Equal(t, "server responded 502: maintenance", err.Error())
+	})
+}
+
+func TestHTTPPreview(t *testing.T) {
+	create := Request{
+		URL:     "https://billing.example.com/entries",
+		Headers: map[string]string{"Authorization": "Bearer token"},
+		Body:    `{"ticket": {{json .Ticket}}, "hours": {{.Hours}}}`,
+		ID:      "$.id",
+	}
+	entry := Request{URL: "https://billing.example.com/entries/{{.ID}}"}
+	full, _ := NewHTTP("billing", create, entry, entry, http.DefaultClient)
+	createOnly, _ := NewHTTP("billing", create, Request{}, Request{}, http.DefaultClient)
+
+	tests := []struct {
+		name   string
+		target *HTTP
+		method string
+		want   string
+		body   any
+		err    string
+	}{
+		{"create", full, http.MethodPost, "https://billing.example.com/entries", json.RawMessage(`{"ticket": "SUP-1", "hours": 1.5}`), ""},
+		{"update", full, http.MethodPut, "https://billing.example.com/entries/e-1", json.RawMessage(`{"ticket": "SUP-1", "hours": 1.5}`), ""},
+		{"delete", full, http.MethodDelete, "https://billing.example.com/entries/e-1", nil, ""},
+		{"unsupported update", createOnly, http.MethodPut, "", nil, "target billing doesn't support PUT"},
+		{"unsupported delete", createOnly, http.MethodDelete, "", nil, "target billing doesn't support DELETE"},
+	}
+	for _, tt := range tests {
+		t.Run("Should preview "+tt.name, func(t *testing.T) {
+			row := model.JiraRequestRow{Method: tt.method, Jiraticket: "SUP-1", Timespent: "1h 30m", Started: "15 Apr 2020 11:30", WorklogID: "e-1"}
+
+			method, url, body, err := tt.target.Preview(row)
+
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.method, method)
+			assert.Equal(t, tt.want, url)
+			assert.Equal(t, tt.body, body)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package target
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/philgal/jtl/internal/duration"
+	"github.com/philgal/jtl/internal/jira"
+	"github.com/philgal/jtl/internal/model"
+)
+
+// Jira pushes records as native Jira worklogs.
+// Before creating a worklog, it looks for the same worklog, already created by the user, and adopts it instead of creating a duplicate.
+type Jira struct {
+	Client *jira.Client
+	// Finder looks for existing worklogs, nil disables the check
+	Finder *jira.WorklogFinder
+}
+
+// Create creates a worklog in the row's ticket, adjusting the remaining estimate as the row says
+func (t *Jira) Create(row model.JiraRequestRow) (*Worklog, *Response, error) {
+	existing, err := t.findExisting(row)
+	if err != nil {
+		// creating the worklog without the check might duplicate it, so the row is left for the next push
+		return nil, &Response{}, fmt.Errorf("cannot check existing worklogs of %v, try again: %w", row.Jiraticket, err)
+	}
+	if existing != nil {
+		log.Printf("Worklog of %v started %v already exists with ID %v, adopting it\n", row.Jiraticket, row.Started, existing.ID)
+		w := fromJira(existing)
+		w.IsAdopted = true
+		return w, &Response{}, nil
+	}
+	w, err := t.worklogCreate(row)
+	if err != nil {
+		return nil, &Response{}, err
+	}
+	worklog, resp, err := t.Client.AddWorklog(row.Jiraticket, w, row.Estimate.Query())
+	return fromJira(worklog), fromJiraResponse(resp), err
+}
+
+// Update updates the worklog
+func (t *Jira) Update(row model.JiraRequestRow) (*Worklog, *Response, error) {
+	w, err := t.worklogCreate(row)
+	if err != nil {
+		return nil, &Response{}, err
+	}
+	worklog, resp, err := t.Client.UpdateWorklog(row.Jiraticket, row.WorklogID, w)
+	return fromJira(worklog), fromJiraResponse(resp), err
+}
+
+// Delete deletes the worklog
+func (t *Jira) Delete(row model.JiraRequestRow) (*Response, error) {
+	resp, err := t.Client.DeleteWorklog(row.Jiraticket, row.WorklogID)
+	return fromJiraResponse(resp), err
+}
+
+// Preview returns the request for the row
+func (t *Jira) Preview(row model.JiraRequestRow) (string, string, any, error) {
+	switch row.Method {
+	case http.MethodPut:
+		w, err := t.worklogCreate(row)
+		return row.Method, t.Client.URL("issue/%v/worklog/%v", row.Jiraticket, row.WorklogID), w, err
+	case http.MethodDelete:
+		return row.Method, t.Client.URL("issue/%v/worklog/%v", row.Jiraticket, row.WorklogID), nil, nil
+	default:
+		worklogURL := t.Client.WorklogURL(row.Jiraticket)
+		if query := row.Estimate.Query(); len(query) > 0 {
+			worklogURL += "?" + query.Encode()
+		}
+		w, err := t.worklogCreate(row)
+		return row.Method, worklogURL, w, err
+	}
+}
+
+// findExisting looks for a worklog created for the row by an earlier, interrupted push
+func (t *Jira) findExisting(row model.JiraRequestRow) (*jira.Worklog, error) {
+	if t.Finder == nil {
+		return nil, nil
+	}
+	started, err := parseStarted(row.Started)
+	if err != nil {
+		return nil, err
+	}
+	return t.Finder.Find(row.Jiraticket, started, duration.ToMinutes(row.Timespent))
+}
+
+func (t *Jira) worklogCreate(row model.JiraRequestRow) (jira.WorklogCreate, error) {
+	started, err := parseStarted(row.Started)
+	if err != nil {
+		return jira.WorklogCreate{}, err
+	}
+	visibility, err := jira.ParseVisibility(row.Visibility)
+	return jira.WorklogCreate{
+		TimeSpentSeconds: duration.ToMinutes(row.Timespent) * 60,
+		Comment:          t.Client.NewComment(row.Comment),
+		Started:          jira.FormatTime(started),
+		Visibility:       visibility,
+	}, err
+}
+
+func fromJira(w *jira.Worklog) *Worklog {
+	if w == nil {
+		return nil
+	}
+	return &Worklog{ID: w.ID, IssueID: w.IssueID, Started: w.Started, TimeSpent: w.TimeSpent, Comment: w.Comment.Text}
+}
+
+func fromJiraResponse(resp *jira.Response) *Response {
+	if resp == nil {
+		return &Response{}
+	}
+	return &Response{StatusCode: resp.StatusCode, Attempts: resp.Attempts}
+}
@@ -0,0 +1,54 @@
+package target
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/philgal/jtl/internal/config"
+	"github.com/philgal/jtl/internal/model"
+)
+
+// Names of supported targets, set by push.target config
+const (
+	NameJira  = "jira"
+	NameTempo = "tempo"
+)
+
+// Target is a remote system, records are pushed to as worklogs, e.g. Jira or Tempo.
+// Implementations must be safe for concurrent use.
+type Target interface {
+	// Create creates a worklog of a new record
+	Create(row model.JiraRequestRow) (*Worklog, *Response, error)
+	// Update updates the worklog of a modified record by row.WorklogID
+	Update(row model.JiraRequestRow) (*Worklog, *Response, error)
+	// Delete deletes the worklog of a removed record by row.WorklogID
+	Delete(row model.JiraRequestRow) (*Response, error)
+	// Preview returns the request, which would be sent for the row, without sending it
+	Preview(row model.JiraRequestRow) (method, url string, body any, err error)
+}
+
+// Worklog is a worklog as stored by a target
+type Worklog struct {
+	ID        string
+	IssueID   string
+	Started   string
+	TimeSpent string
+	Comment   string
+	// IsAdopted is true if the worklog already existed and was not created
+	IsAdopted bool
+}
+
+// Response describes how a request went, it is returned even if the request failed
+type Response struct {
+	StatusCode int
+	Attempts   int
+}
+
+// parseStarted parses started of a data file record in local time
+func parseStarted(started string) (time.Time, error) {
+	t, err := time.ParseInLocation(config.DefaultDateTimePattern, started, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("couldn't parse date, %w", err)
+	}
+	return t, nil
+}
@@ -0,0 +1,174 @@
+package target
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/philgal/jtl/internal/duration"
+	"github.com/philgal/jtl/internal/jira"
+	"github.com/philgal/jtl/internal/model"
+	"github.com/philgal/jtl/internal/tempo"
+)
+
+// Tempo pushes records as Tempo Timesheets worklogs with configured work attributes, e.g. account and work type.
+// Tempo identifies issues and authors by Jira IDs, they are resolved with Jira client once per issue and per push.
+type Tempo struct {
+	Client *tempo.Client
+	Jira   *jira.Client
+	// AccountID is a Jira account ID of the worklogs' author, resolved from Jira if it's empty
+	AccountID string
+	// Attributes are work attributes of every worklog
+	Attributes []tempo.Attribute
+	// ProjectAttributes are work attributes of worklogs by project key, overriding Attributes with the same key
+	ProjectAttributes map[string][]tempo.Attribute
+
+	mu       sync.Mutex
+	issueIDs map[string]int
+}
+
+// Create creates a Tempo worklog
+func (t *Tempo) Create(row model.JiraRequestRow) (*Worklog, *Response, error) {
+	w, err := t.worklogCreate(row, true)
+	if err != nil {
+		return nil, &Response{}, err
+	}
+	worklog, resp, err := t.Client.AddWorklog(w)
+	return fromTempo(worklog), fromTempoResponse(resp), err
+}
+
+// Update updates the Tempo worklog
+func (t *Tempo) Update(row model.JiraRequestRow) (*Worklog, *Response, error) {
+	w, err := t.worklogCreate(row, true)
+	if err != nil {
+		return nil, &Response{}, err
+	}
+	worklog, resp, err := t.Client.UpdateWorklog(row.WorklogID, w)
+	return fromTempo(worklog), fromTempoResponse(resp), err
+}
+
+// Delete deletes the Tempo worklog
+func (t *Tempo) Delete(row model.JiraRequestRow) (*Response, error) {
+	resp, err := t.Client.DeleteWorklog(row.WorklogID)
+	return fromTempoResponse(resp), err
+}
+
+// Preview returns the request for the row. Issue and author IDs are not resolved, as it requires requests to Jira.
+func (t *Tempo) Preview(row model.JiraRequestRow) (string, string, any, error) {
+	switch row.Method {
+	case http.MethodDelete:
+		return row.Method, t.Client.WorklogURL(row.WorklogID), nil, nil
+	case http.MethodPut:
+		w, err := t.worklogCreate(row, false)
+		return row.Method, t.Client.WorklogURL(row.WorklogID), w, err
+	default:
+		w, err := t.worklogCreate(row, false)
+		return row.Method, t.Client.WorklogURL(""), w, err
+	}
+}
+
+// AttributesFor returns work attributes of the ticket's worklogs
+func (t *Tempo) AttributesFor(ticket string) []tempo.Attribute {
+	project, _, _ := strings.Cut(strings.ToUpper(ticket), "-")
+	attrs := append([]tempo.Attribute{}, t.Attributes...)
+	for _, pa := range t.ProjectAttributes[project] {
+		replaced := false
+		for i := range attrs {
+			if attrs[i].Key == pa.Key {
+				attrs[i].Value, replaced = pa.Value, true
+			}
+		}
+		if !replaced {
+			attrs = append(attrs, pa)
+		}
+	}
+	return attrs
+}
+
+func (t *Tempo) worklogCreate(row model.JiraRequestRow, resolveIDs bool) (tempo.WorklogCreate, error) {
+	started, err := parseStarted(row.Started)
+	if err != nil {
+		return tempo.WorklogCreate{}, err
+	}
+	w := tempo.WorklogCreate{
+		TimeSpentSeconds: duration.ToMinutes(row.Timespent) * 60,
+		StartDate:        started.Format(tempo.DatePattern),
+		StartTime:        started.Format(tempo.TimePattern),
+		Description:      row.Comment,
+		Attributes:       t.AttributesFor(row.Jiraticket),
+	}
+	if !resolveIDs {
+		return w, nil
+	}
+	if w.IssueID, err = t.issueID(row.Jiraticket); err != nil {
+		return w, fmt.Errorf("cannot resolve ID of %v: %w", row.Jiraticket, err)
+	}
+	if w.AuthorAccountID, err = t.accountID(); err != nil {
+		return w, fmt.Errorf("cannot resolve account ID of the current user: %w", err)
+	}
+	return w, nil
+}
+
+func (t *Tempo) issueID(key string) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if id, found := t.issueIDs[key]; found {
+		return id, nil
+	}
+	issue, err := t.Jira.Issue(key, "key")
+	if err != nil {
+		return 0, err
+	}
+	id, err := strconv.Atoi(issue.ID)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected issue ID %q", issue.ID)
+	}
+	if t.issueIDs == nil {
+		t.issueIDs = map[string]int{}
+	}
+	t.issueIDs[key] = id
+	return id, nil
+}
+
+func (t *Tempo) accountID() (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.AccountID != "" {
+		return t.AccountID, nil
+	}
+	me, err := t.Jira.Myself()
+	if err != nil {
+		return "", err
+	}
+	if me.AccountID == "" {
+		return "", fmt.Errorf("jira user %v has no account ID, set tempo.accountId", me.Name)
+	}
+	t.AccountID = me.AccountID
+	return t.AccountID, nil
+}
+
+func fromTempo(w *tempo.Worklog) *Worklog {
+	if w == nil {
+		return nil
+	}
+	started := w.StartDate + " " + w.StartTime
+	if t, err := w.Started(); err == nil {
+		started = jira.FormatTime(t)
+	}
+	return &Worklog{
+		ID:        strconv.Itoa(w.TempoWorklogID),
+		IssueID:   strconv.Itoa(w.Issue.ID),
+		Started:   started,
+		TimeSpent: duration.ToString(w.TimeSpentSeconds / 60),
+		Comment:   w.Description,
+	}
+}
+
+func fromTempoResponse(resp *tempo.Response) *Response {
+	if resp == nil {
+		return &Response{}
+	}
+	return &Response{StatusCode: resp.StatusCode, Attempts: resp.Attempts}
+}
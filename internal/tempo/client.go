@@ -0,0 +1,138 @@
+package tempo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/philgal/jtl/internal/rest"
+)
+
+// DefaultURL is the base URL of Tempo Cloud REST API
+const DefaultURL = "https://api.tempo.io/4"
+
+// Client is a typed client of Tempo REST API
+type Client struct {
+	URL   string
+	Token string
+	HTTP  rest.Client
+	Retry rest.RetryPolicy
+}
+
+// Response describes how a request went, it is returned even if the request failed
+type Response struct {
+	StatusCode int
+	Attempts   int
+}
+
+// NewClient creates a Client for the base URL, falling back to DefaultURL if it's empty
+func NewClient(baseURL, token string, httpClient rest.Client) *Client {
+	if baseURL = strings.TrimSpace(baseURL); baseURL == "" {
+		baseURL = DefaultURL
+	}
+	return &Client{URL: strings.TrimSuffix(baseURL, "/"), Token: token, HTTP: httpClient}
+}
+
+// WorklogURL returns URL of the worklog by ID, or of all worklogs if id is empty
+func (c *Client) WorklogURL(id string) string {
+	if id == "" {
+		return c.URL + "/worklogs"
+	}
+	return c.URL + "/worklogs/" + url.PathEscape(id)
+}
+
+// NewRequest creates an authorized request with body encoded as JSON
+func (c *Client) NewRequest(method, url string, body any) (*http.Request, error) {
+	if c.Token == "" {
+		return nil, fmt.Errorf("tempo API token is required")
+	}
+	var reader io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(jsonBody)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Authorization", "Bearer "+c.Token)
+	req.Header.Add("Accept", "application/json")
+	if body != nil {
+		req.Header.Add("Content-Type", "application/json")
+	}
+	log.Printf("[Prepared HTTP Request] %v %v\n", req.Method, req.URL)
+	return req, nil
+}
+
+// Do sends the request with retries and decodes a successful response into v.
+// Unsuccessful responses are returned as *ErrorResponse error.
+func (c *Client) Do(req *http.Request, v any) (*Response, error) {
+	res, attempts, err := rest.DoWithRetry(c.HTTP, req, c.Retry)
+	resp := &Response{Attempts: attempts}
+	if err != nil {
+		log.Printf("Failed to send %v %v after %v attempt(s): %v\n", req.Method, req.URL, attempts, err)
+		return resp, err
+	}
+	defer res.Body.Close()
+	resp.StatusCode = res.StatusCode
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return resp, fmt.Errorf("failed to read response body: %w", err)
+	}
+	log.Printf("Tempo server responded: %v\n{%q}\n", res.Status, body)
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		errs := &ErrorResponse{StatusCode: res.StatusCode}
+		if len(body) > 0 {
+			if err := json.Unmarshal(body, errs); err != nil {
+				log.Println("Error unmarshalling error response:", err)
+			}
+		}
+		return resp, errs
+	}
+	if v != nil && len(body) > 0 {
+		if err := json.Unmarshal(body, v); err != nil {
+			return resp, fmt.Errorf("error unmarshalling response: %w", err)
+		}
+	}
+	return resp, nil
+}
+
+// AddWorklog creates a worklog
+func (c *Client) AddWorklog(w WorklogCreate) (*Worklog, *Response, error) {
+	return c.send(http.MethodPost, c.WorklogURL(""), w)
+}
+
+// UpdateWorklog updates the worklog by ID
+func (c *Client) UpdateWorklog(id string, w WorklogCreate) (*Worklog, *Response, error) {
+	return c.send(http.MethodPut, c.WorklogURL(id), w)
+}
+
+// DeleteWorklog deletes the worklog by ID
+func (c *Client) DeleteWorklog(id string) (*Response, error) {
+	req, err := c.NewRequest(http.MethodDelete, c.WorklogURL(id), nil)
+	if err != nil {
+		return &Response{}, err
+	}
+	return c.Do(req, nil)
+}
+
+func (c *Client) send(method, url string, w WorklogCreate) (*Worklog, *Response, error) {
+	req, err := c.NewRequest(method, url, w)
+	if err != nil {
+		return nil, &Response{}, err
+	}
+	worklog := &Worklog{}
+	resp, err := c.Do(req, worklog)
+	if err != nil {
+		return nil, resp, err
+	}
+	return worklog, resp, nil
+}
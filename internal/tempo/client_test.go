@@ -0,0 +1,62 @@
+package tempo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient(t *testing.T) {
+	var gotMethod, gotPath, gotAuth string
+	var gotBody map[string]any
+	status, response := http.StatusOK, `{"tempoWorklogId":42,"issue":{"id":10002},"timeSpentSeconds":3600,"startDate":"2020-04-15","startTime":"11:30:00","description":"Demo"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath, gotAuth = r.Method, r.URL.Path, r.Header.Get("Authorization")
+		gotBody = nil
+		json.NewDecoder(r.Body).Decode(&gotBody)
+		w.WriteHeader(status)
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	client := NewClient(server.URL+"/4/", "tempo-token", server.Client())
+
+	t.Run("Should create worklog with attributes", func(t *testing.T) {
+		worklog, resp, err := client.AddWorklog(WorklogCreate{
+			IssueID: 10002, AuthorAccountID: "acc-1", TimeSpentSeconds: 3600, StartDate: "2020-04-15", StartTime: "11:30:00",
+			Description: "Demo", Attributes: []Attribute{{Key: "_Account_", Value: "ACC-1"}},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, 42, worklog.TempoWorklogID)
+		assert.Equal(t, "POST /4/worklogs", gotMethod+" "+gotPath)
+		assert.Equal(t, "Bearer tempo-token", gotAuth)
+		assert.Equal(t, float64(10002), gotBody["issueId"])
+		assert.Equal(t, []any{map[string]any{"key": "_Account_", "value": "ACC-1"}}, gotBody["attributes"])
+	})
+
+	t.Run("Should update and delete worklog by ID", func(t *testing.T) {
+		_, _, err := client.UpdateWorklog("42", WorklogCreate{TimeSpentSeconds: 60})
+		assert.NoError(t, err)
+		assert.Equal(t, "PUT /4/worklogs/42", gotMethod+" "+gotPath)
+
+		status, response = http.StatusNoContent, ""
+		_, err = client.DeleteWorklog("42")
+		assert.NoError(t, err)
+		assert.Equal(t, "DELETE /4/worklogs/42", gotMethod+" "+gotPath)
+	})
+
+	t.Run("Should return error response on failure", func(t *testing.T) {
+		status, response = http.StatusBadRequest, `{"errors":[{"message":"Account is required"}]}`
+
+		_, resp, err := client.AddWorklog(WorklogCreate{})
+
+		var errs *ErrorResponse
+		assert.ErrorAs(t, err, &errs)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "Account is required", err.Error())
+	})
+}
@@ -0,0 +1,77 @@
+package tempo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Formats of Tempo worklog start date and time
+const (
+	DatePattern = "2006-01-02"
+	TimePattern = "15:04:05"
+)
+
+// WorklogCreate is a request body of a new or updated Tempo worklog
+type WorklogCreate struct {
+	IssueID          int         `json:"issueId,omitempty"`
+	AuthorAccountID  string      `json:"authorAccountId,omitempty"`
+	TimeSpentSeconds int         `json:"timeSpentSeconds"`
+	StartDate        string      `json:"startDate"`
+	StartTime        string      `json:"startTime"`
+	Description      string      `json:"description"`
+	Attributes       []Attribute `json:"attributes,omitempty"`
+}
+
+// Attribute is a work attribute of a worklog, e.g. account or work type. Key is a Tempo attribute key, e.g. _Account_.
+type Attribute struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Worklog is a worklog as returned by Tempo
+type Worklog struct {
+	Self             string `json:"self,omitempty"`
+	TempoWorklogID   int    `json:"tempoWorklogId"`
+	Issue            Issue  `json:"issue"`
+	TimeSpentSeconds int    `json:"timeSpentSeconds"`
+	StartDate        string `json:"startDate"`
+	StartTime        string `json:"startTime"`
+	Description      string `json:"description"`
+	Attributes       struct {
+		Values []Attribute `json:"values"`
+	} `json:"attributes"`
+}
+
+// Issue is a reference to the Jira issue of a worklog
+type Issue struct {
+	ID int `json:"id"`
+}
+
+// Started returns the worklog's start in local time
+func (w Worklog) Started() (time.Time, error) {
+	startTime := w.StartTime
+	if startTime == "" {
+		startTime = "00:00:00"
+	}
+	return time.ParseInLocation(DatePattern+" "+TimePattern, w.StartDate+" "+startTime, time.Local)
+}
+
+// ErrorResponse is an error body returned by Tempo for unsuccessful requests
+type ErrorResponse struct {
+	StatusCode int `json:"-"`
+	Errors     []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func (e *ErrorResponse) Error() string {
+	var messages []string
+	for _, err := range e.Errors {
+		messages = append(messages, err.Message)
+	}
+	if len(messages) == 0 {
+		return fmt.Sprintf("tempo server responded %v", e.StatusCode)
+	}
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,160 @@
+package timer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/philgal/jtl/internal/config"
+	"github.com/philgal/jtl/internal/duration"
+)
+
+// Timer is a running timer of work on a ticket, kept in a state file between commands
+type Timer struct {
+	Ticket     string    `json:"ticket"`
+	Comment    string    `json:"comment"`
+	Visibility string    `json:"visibility,omitempty"`
+	Started    time.Time `json:"started"`
+}
+
+// Span is a part of the timer's time within a single day
+type Span struct {
+	Started  time.Time
+	Duration time.Duration
+}
+
+// DefaultPath returns path of the timer state file in jtl home directory
+func DefaultPath() string {
+	return path.Join(config.Dir(), "timer.json")
+}
+
+// Load reads the running timer, nil if no timer is running
+func Load(path string) (*Timer, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	t := &Timer{}
+	if err := json.Unmarshal(data, t); err != nil {
+		return nil, fmt.Errorf("invalid timer state %v: %w", path, err)
+	}
+	return t, nil
+}
+
+// Save writes the timer as the running one
+func (t *Timer) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Clear removes the running timer
+func Clear(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Elapsed returns time passed since the timer started
+func (t *Timer) Elapsed(now time.Time) time.Duration {
+	return now.Sub(t.Started)
+}
+
+// Split splits time of the timer stopped at the moment into spans of each day, split at local midnight
+func (t *Timer) Split(stopped time.Time) []Span {
+	var spans []Span
+	start := t.Started
+	for start.Before(stopped) {
+		y, m, d := start.Date()
+		midnight := time.Date(y, m, d+1, 0, 0, 0, 0, start.Location())
+		end := stopped
+		if midnight.Before(stopped) {
+			end = midnight
+		}
+		spans = append(spans, Span{Started: start, Duration: end.Sub(start)})
+		start = end
+	}
+	return spans
+}
+
+const (
+	RoundNearest = "nearest"
+	RoundUp      = "up"
+	RoundDown    = "down"
+	RoundNone    = "none"
+)
+
+// Rounding is a policy of rounding tracked time, e.g. up to 15 minutes
+type Rounding struct {
+	Mode string
+	To   time.Duration
+}
+
+// ParseRounding parses a rounding policy: none, or <nearest|up|down>:<duration>, e.g. up:15m
+func ParseRounding(s string) (Rounding, error) {
+	mode, to, _ := strings.Cut(strings.ToLower(strings.TrimSpace(s)), ":")
+	if mode == RoundNone || mode == "" {
+		return Rounding{Mode: RoundNone}, nil
+	}
+	if mode != RoundNearest && mode != RoundUp && mode != RoundDown {
+		return Rounding{}, fmt.Errorf("unknown rounding %q, expected none, nearest, up or down", mode)
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(to))
+	if err != nil || d < time.Minute {
+		return Rounding{}, fmt.Errorf("invalid rounding %q, expected e.g. %v:15m", s, mode)
+	}
+	return Rounding{Mode: mode, To: d}, nil
+}
+
+// Round rounds the duration to whole minutes by the policy
+func (r Rounding) Round(d time.Duration) time.Duration {
+	switch r.Mode {
+	case RoundNearest:
+		return d.Round(r.To)
+	case RoundUp:
+		if rounded := d.Truncate(r.To); rounded < d {
+			return rounded + r.To
+		}
+		return d
+	case RoundDown:
+		return d.Truncate(r.To)
+	default:
+		return d.Round(time.Minute)
+	}
+}
+
+// RoundSpans rounds the total duration of the spans by the policy, rather than each span separately, so a short timer across midnight
+// is not rounded up twice. Returns rounded durations of the spans, summing up to the rounded total: each span gets the difference
+// of the rounded durations elapsed by its end and by its start, which may be zero.
+func (r Rounding) RoundSpans(spans []Span) []time.Duration {
+	rounded := make([]time.Duration, len(spans))
+	var elapsed, roundedElapsed time.Duration
+	for i, span := range spans {
+		elapsed += span.Duration
+		next := r.Round(elapsed)
+		rounded[i] = next - roundedElapsed
+		roundedElapsed = next
+	}
+	return rounded
+}
+
+func (r Rounding) String() string {
+	if r.Mode == RoundNone || r.Mode == "" {
+		return RoundNone
+	}
+	return fmt.Sprintf("%v:%v", r.Mode, duration.ToString(int(r.To/time.Minute)))
+}
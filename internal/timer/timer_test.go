@@ -0,0 +1,93 @@
+package timer
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimer(t *testing.T) {
+	at := func(day, hour, min int) time.Time { return time.Date(2020, 4, day, hour, min, 0, 0, time.Local) }
+
+	t.Run("Should save, load and clear timer", func(t *testing.T) {
+		statePath := path.Join(t.TempDir(), "timer.json")
+		running, err := Load(statePath)
+		assert.NoError(t, err)
+		assert.Nil(t, running)
+
+		timer := &Timer{Ticket: "SUP-1", Comment: "support", Started: at(14, 9, 30)}
+		assert.NoError(t, timer.Save(statePath))
+		running, err = Load(statePath)
+		assert.NoError(t, err)
+		assert.Equal(t, "SUP-1", running.Ticket)
+		assert.True(t, timer.Started.Equal(running.Started))
+
+		assert.NoError(t, Clear(statePath))
+		running, _ = Load(statePath)
+		assert.Nil(t, running)
+	})
+
+	t.Run("Should keep timer within a day in one span", func(t *testing.T) {
+		timer := &Timer{Started: at(14, 9, 30)}
+
+		assert.Equal(t, []Span{{Started: at(14, 9, 30), Duration: 2 * time.Hour}}, timer.Split(at(14, 11, 30)))
+	})
+
+	t.Run("Should split timer running across midnight per day", func(t *testing.T) {
+		timer := &Timer{Started: at(14, 22, 0)}
+
+		assert.Equal(t, []Span{
+			{Started: at(14, 22, 0), Duration: 2 * time.Hour},
+			{Started: at(15, 0, 0), Duration: 24 * time.Hour},
+			{Started: at(16, 0, 0), Duration: 90 * time.Minute},
+		}, timer.Split(at(16, 1, 30)))
+	})
+}
+
+func TestRounding(t *testing.T) {
+	tests := []struct {
+		policy string
+		in     time.Duration
+		want   time.Duration
+	}{
+		{"none", 52*time.Minute + 40*time.Second, 53 * time.Minute},
+		{"nearest:15m", 52 * time.Minute, 45 * time.Minute},
+		{"nearest:15m", 53 * time.Minute, time.Hour},
+		{"up:15m", 46 * time.Minute, time.Hour},
+		{"up:15m", 45 * time.Minute, 45 * time.Minute},
+		{"down:15m", 59 * time.Minute, 45 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run("Should round "+tt.in.String()+" by "+tt.policy, func(t *testing.T) {
+			r, err := ParseRounding(tt.policy)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, r.Round(tt.in))
+			assert.Equal(t, tt.policy, r.String())
+		})
+	}
+
+	t.Run("Should round total of spans across midnight", func(t *testing.T) {
+		up, _ := ParseRounding("up:15m")
+		spans := []Span{{Duration: 5 * time.Minute}, {Duration: 5 * time.Minute}}
+
+		assert.Equal(t, []time.Duration{15 * time.Minute, 0}, up.RoundSpans(spans))
+	})
+
+	t.Run("Should keep sum of rounded spans equal to rounded total", func(t *testing.T) {
+		nearest, _ := ParseRounding("nearest:15m")
+		spans := []Span{{Duration: 50 * time.Minute}, {Duration: 24 * time.Hour}, {Duration: 20 * time.Minute}}
+
+		assert.Equal(t, []time.Duration{45 * time.Minute, 24 * time.Hour, 30 * time.Minute}, nearest.RoundSpans(spans))
+	})
+
+	t.Run("Should reject invalid policies", func(t *testing.T) {
+		_, err := ParseRounding("ceil:15m")
+		assert.ErrorContains(t, err, "unknown rounding")
+
+		_, err = ParseRounding("up")
+		assert.ErrorContains(t, err, "invalid rounding")
+	})
+}
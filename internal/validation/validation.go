@@ -15,6 +15,7 @@ var Validate *validator.Validate
 const (
 	jiraTicketRegexp = `(([A-Za-z]{1,10})-?)[A-Z]+-\d+`
 	timeSpentRegexp  = `^(\d+d)? ?(\d+h)? ?(\d+m)?$`
+	visibilityRegexp = `^((?i:role|group):\s*\S.*)?$`
 )
 
 //InitValidator initializes validation and registers custom validators
@@ -22,6 +23,7 @@ func InitValidator() {
 	Validate = validator.New()
 	Validate.RegisterValidation("jiraticket", validateJiraTicketName)
 	Validate.RegisterValidation("timespent", validateTimeSpent)
+	Validate.RegisterValidation("visibility", validateVisibility)
 }
 
 func validateJiraTicketName(fl validator.FieldLevel) bool {
@@ -32,6 +34,10 @@ func validateTimeSpent(fl validator.FieldLevel) bool {
 	return validateRegexp(fl, timeSpentRegexp, "Error validating timeSpent")
 }
 
+func validateVisibility(fl validator.FieldLevel) bool {
+	return validateRegexp(fl, visibilityRegexp, "Error validating visibility")
+}
+
 func validateRegexp(fl validator.FieldLevel, regexpPattern string, message string) bool {
 	val := strings.Trim(fl.Field().String(), " ")
 	match, err := regexp.MatchString(regexpPattern, val)